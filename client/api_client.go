@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	nsxt "github.com/vmware/go-vmware-nsxt"
+)
+
+// APIClient is the generated NSX-T client together with the HTTP client it
+// sends requests with, which is used to call APIs the generated client does
+// not cover, like the Policy API.
+type APIClient struct {
+	*nsxt.APIClient
+	httpClient *http.Client
+	scheme     string
+	host       string
+	userAgent  string
+}
+
+// NewAPIClient returns a client for the NSX-T manager given by cfg.
+func NewAPIClient(cfg *nsxt.Configuration) (*APIClient, error) {
+	apiClient, err := nsxt.NewAPIClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &APIClient{
+		APIClient:  apiClient,
+		httpClient: cfg.HTTPClient,
+		scheme:     cfg.Scheme,
+		host:       cfg.Host,
+		userAgent:  cfg.UserAgent,
+	}, nil
+}
+
+// get sends a GET request for path with the given query and decodes the JSON
+// response into v. Unlike the generated client, the request is cancelled once
+// ctx is done.
+func (c *APIClient) get(ctx context.Context, path string, query url.Values, v interface{}) error {
+	u := url.URL{
+		Scheme:   c.scheme,
+		Host:     c.host,
+		Path:     path,
+		RawQuery: query.Encode(),
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s: %s", path, resp.Status, body)
+	}
+	return json.Unmarshal(body, v)
+}
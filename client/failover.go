@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	apiActiveEndpoint = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "nsxt",
+		Subsystem: "api",
+		Name:      "active_endpoint",
+		Help:      "Whether the NSX-T manager node is the one requests are sent to.",
+	}, []string{"host"})
+
+	clustersMtx sync.Mutex
+	clusters    = make(map[string]*cluster)
+)
+
+func init() {
+	prometheus.MustRegister(apiActiveEndpoint)
+}
+
+// cluster holds the manager nodes of a NSX-T cluster and the node that
+// answered last.
+type cluster struct {
+	hosts []string
+
+	mtx    sync.Mutex
+	active int
+}
+
+func (c *cluster) activeHost() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.active
+}
+
+func (c *cluster) setActive(active int) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.active = active
+	for i, host := range c.hosts {
+		value := 0.0
+		if i == active {
+			value = 1.0
+		}
+		apiActiveEndpoint.WithLabelValues(host).Set(value)
+	}
+}
+
+// failoverTransport sends requests to the active node of a cluster and fails
+// over to the next node on connection errors and 503. Requests whose context
+// is done are not sent to other nodes.
+type failoverTransport struct {
+	next    http.RoundTripper
+	cluster *cluster
+	logger  log.Logger
+}
+
+// NewFailoverTransport returns a RoundTripper sending requests through next
+// to one of hosts, starting with the first. Once a node answered, requests
+// are sent to it until it fails. Transports with the same hosts share the
+// node they send requests to.
+func NewFailoverTransport(next http.RoundTripper, hosts []string, logger log.Logger) http.RoundTripper {
+	key := strings.Join(hosts, ",")
+	clustersMtx.Lock()
+	c, ok := clusters[key]
+	if !ok {
+		c = &cluster{hosts: hosts}
+		c.setActive(0)
+		clusters[key] = c
+	}
+	clustersMtx.Unlock()
+	return &failoverTransport{
+		next:    next,
+		cluster: c,
+		logger:  logger,
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	active := t.cluster.activeHost()
+	var resp *http.Response
+	var err error
+	for n := 0; n < len(t.cluster.hosts); n++ {
+		i := (active + n) % len(t.cluster.hosts)
+		nodeReq, ok := rewindRequest(req)
+		if !ok {
+			if n > 0 {
+				break
+			}
+			nodeReq = req
+		}
+		nodeReq = nodeReq.Clone(nodeReq.Context())
+		nodeReq.URL.Host = t.cluster.hosts[i]
+		nodeReq.Host = ""
+		if resp != nil {
+			resp.Body.Close()
+		}
+		resp, err = t.next.RoundTrip(nodeReq)
+		if err != nil && (req.Context().Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+			return nil, err
+		}
+		if err == nil && resp.StatusCode != http.StatusServiceUnavailable {
+			if i != active {
+				level.Info(t.logger).Log("msg", "Failed over to NSX-T manager node", "host", t.cluster.hosts[i])
+				t.cluster.setActive(i)
+			}
+			return resp, nil
+		}
+		level.Debug(t.logger).Log("msg", "NSX-T manager node unavailable", "host", t.cluster.hosts[i], "err", err)
+	}
+	return resp, err
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeNode is a NSX-T manager node answering with status and counting the
+// requests it received.
+type fakeNode struct {
+	server *httptest.Server
+	host   string
+
+	mtx      sync.Mutex
+	status   int
+	requests int
+}
+
+func newFakeNode(status int) *fakeNode {
+	n := &fakeNode{status: status}
+	n.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n.mtx.Lock()
+		defer n.mtx.Unlock()
+		n.requests++
+		w.WriteHeader(n.status)
+	}))
+	u, _ := url.Parse(n.server.URL)
+	n.host = u.Host
+	return n
+}
+
+func (n *fakeNode) requestCount() int {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return n.requests
+}
+
+func (n *fakeNode) setStatus(status int) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.status = status
+}
+
+func sendFailoverRequest(ctx context.Context, transport http.RoundTripper) (int, error) {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://nsx.example.com/api/v1/fabric/nodes", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func TestFailoverTransport_RoundTrip(t *testing.T) {
+	nodes := []*fakeNode{newFakeNode(http.StatusOK), newFakeNode(http.StatusOK), newFakeNode(http.StatusOK)}
+	hosts := make([]string, len(nodes))
+	for i, n := range nodes {
+		defer n.server.Close()
+		hosts[i] = n.host
+	}
+	transport := NewFailoverTransport(http.DefaultTransport, hosts, log.NewNopLogger())
+	activeEndpoints := func() []float64 {
+		var values []float64
+		for _, host := range hosts {
+			values = append(values, testutil.ToFloat64(apiActiveEndpoint.WithLabelValues(host)))
+		}
+		return values
+	}
+
+	status, err := sendFailoverRequest(context.Background(), transport)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []float64{1, 0, 0}, activeEndpoints(), "Should send requests to first node")
+
+	nodes[0].setStatus(http.StatusServiceUnavailable)
+	status, err = sendFailoverRequest(context.Background(), transport)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []float64{0, 1, 0}, activeEndpoints(), "Should fail over to next node on 503")
+
+	nodes[1].server.Close()
+	status, err = sendFailoverRequest(context.Background(), transport)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []float64{0, 0, 1}, activeEndpoints(), "Should fail over to next node on connection error")
+
+	nodes[0].setStatus(http.StatusOK)
+	status, err = sendFailoverRequest(context.Background(), transport)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []float64{0, 0, 1}, activeEndpoints(), "Should keep sending requests to node that answered")
+	assert.Equal(t, 2, nodes[2].requestCount(), "Should keep sending requests to node that answered")
+
+	nodes[2].setStatus(http.StatusServiceUnavailable)
+	status, err = sendFailoverRequest(context.Background(), transport)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []float64{1, 0, 0}, activeEndpoints(), "Should wrap around to first node")
+
+	for _, n := range nodes {
+		n.setStatus(http.StatusServiceUnavailable)
+	}
+	status, err = sendFailoverRequest(context.Background(), transport)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, status, "Should return last response when every node is unavailable")
+}
+
+// roundTripperFunc sends requests by calling the function.
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestFailoverTransport_RoundTripContextDone(t *testing.T) {
+	testcases := []struct {
+		description string
+		cancel      bool
+		err         error
+	}{
+		{
+			description: "Should not fail over when request is canceled",
+			cancel:      true,
+			err:         context.Canceled,
+		},
+		{
+			description: "Should not fail over when request times out",
+			err:         &url.Error{Op: "Get", URL: "https://nsx-01.example.com", Err: context.DeadlineExceeded},
+		},
+	}
+	for i, tc := range testcases {
+		hosts := []string{fmt.Sprintf("nsx-%d-a.example.com", i), fmt.Sprintf("nsx-%d-b.example.com", i)}
+		ctx, cancel := context.WithCancel(context.Background())
+		var sent []string
+		next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent = append(sent, req.URL.Host)
+			if tc.cancel {
+				cancel()
+			}
+			return nil, tc.err
+		})
+		transport := NewFailoverTransport(next, hosts, log.NewNopLogger())
+
+		_, err := sendFailoverRequest(ctx, transport)
+		assert.True(t, errors.Is(err, tc.err), tc.description)
+		assert.Equal(t, hosts[:1], sent, tc.description)
+		assert.Equal(t, 1.0, testutil.ToFloat64(apiActiveEndpoint.WithLabelValues(hosts[0])), tc.description)
+		cancel()
+	}
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "nsxt",
+		Subsystem: "api",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of NSX-T API requests.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"endpoint", "method", "code"})
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "nsxt",
+		Subsystem: "api",
+		Name:      "requests_total",
+		Help:      "Number of NSX-T API requests.",
+	}, []string{"endpoint", "method", "code"})
+
+	idPattern = regexp.MustCompile(`^([0-9]+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})$`)
+
+	// idCollections are the path segments followed by the ID of an object
+	// in the collection, for IDs that don't match idPattern.
+	idCollections = map[string]bool{
+		"domains":              true,
+		"edge-clusters":        true,
+		"logical-router-ports": true,
+		"logical-routers":      true,
+		"logical-switches":     true,
+		"logical-ports":        true,
+		"ports":                true,
+		"rules":                true,
+		"security-policies":    true,
+		"sections":             true,
+		"segments":             true,
+		"servers":              true,
+		"sessions":             true,
+		"tier-0s":              true,
+		"tier-1s":              true,
+		"transport-nodes":      true,
+	}
+)
+
+func init() {
+	prometheus.MustRegister(apiRequestDuration)
+	prometheus.MustRegister(apiRequests)
+}
+
+// instrumentedTransport records the duration and number of requests per
+// endpoint.
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+// NewInstrumentedTransport returns a RoundTripper sending requests through
+// next and recording them in the nsxt_api_* metrics.
+func NewInstrumentedTransport(next http.RoundTripper) http.RoundTripper {
+	return &instrumentedTransport{next: next}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	endpoint := normalizePath(req.URL.Path)
+	apiRequestDuration.WithLabelValues(endpoint, req.Method, code).Observe(time.Since(start).Seconds())
+	apiRequests.WithLabelValues(endpoint, req.Method, code).Inc()
+	return resp, err
+}
+
+// normalizePath replaces the object IDs in path with ":id" and drops the
+// query string, so that requests for different objects of a kind share an
+// endpoint.
+func normalizePath(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if idPattern.MatchString(segment) || (i > 0 && idCollections[segments[i-1]] && segment != "") {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePath(t *testing.T) {
+	testcases := []struct {
+		description      string
+		path             string
+		expectedEndpoint string
+	}{
+		{
+			description:      "Should keep path without IDs",
+			path:             "/api/v1/fabric/nodes",
+			expectedEndpoint: "/api/v1/fabric/nodes",
+		},
+		{
+			description:      "Should replace UUID",
+			path:             "/api/v1/fabric/nodes/4a4a3a3e-2fce-11e9-8e8b-000c29f6a8b0/status",
+			expectedEndpoint: "/api/v1/fabric/nodes/:id/status",
+		},
+		{
+			description:      "Should replace every UUID",
+			path:             "/api/v1/logical-routers/0f7d7e6a-8b9c-4d3e-9f1a-2b3c4d5e6f70/routing/bfd-peers/status",
+			expectedEndpoint: "/api/v1/logical-routers/:id/routing/bfd-peers/status",
+		},
+		{
+			description:      "Should replace numeric ID",
+			path:             "/api/v1/firewall/rules/1024/state",
+			expectedEndpoint: "/api/v1/firewall/rules/:id/state",
+		},
+		{
+			description:      "Should replace non-UUID ID in known collection",
+			path:             "/policy/api/v1/infra/tier-0s/vrf-tenant-a/state",
+			expectedEndpoint: "/policy/api/v1/infra/tier-0s/:id/state",
+		},
+		{
+			description:      "Should replace nested non-UUID IDs in known collections",
+			path:             "/policy/api/v1/infra/domains/default/security-policies/web-tier/rules/allow-https/statistics",
+			expectedEndpoint: "/policy/api/v1/infra/domains/:id/security-policies/:id/rules/:id/statistics",
+		},
+		{
+			description:      "Should replace non-UUID ID of segment",
+			path:             "/policy/api/v1/infra/segments/web-segment/state",
+			expectedEndpoint: "/policy/api/v1/infra/segments/:id/state",
+		},
+		{
+			description:      "Should keep trailing slash of collection",
+			path:             "/policy/api/v1/infra/segments/",
+			expectedEndpoint: "/policy/api/v1/infra/segments/",
+		},
+		{
+			description:      "Should drop query string",
+			path:             "/api/v1/logical-switches/bd5b2b8e-6e02-4b5a-9c8f-1a2b3c4d5e6f/summary?source=realtime",
+			expectedEndpoint: "/api/v1/logical-switches/:id/summary",
+		},
+		{
+			description:      "Should drop query string of path without IDs",
+			path:             "/api/v1/transport-nodes?cursor=0042&page_size=100",
+			expectedEndpoint: "/api/v1/transport-nodes",
+		},
+	}
+	for _, tc := range testcases {
+		assert.Equal(t, tc.expectedEndpoint, normalizePath(tc.path), tc.description)
+	}
+}
@@ -1,6 +1,10 @@
 package client
 
 import (
+	"context"
+	"nsxt_exporter/client/routing"
+	"nsxt_exporter/client/vpn"
+
 	"github.com/go-kit/kit/log"
 	nsxt "github.com/vmware/go-vmware-nsxt"
 	"github.com/vmware/go-vmware-nsxt/administration"
@@ -8,25 +12,55 @@ import (
 	"github.com/vmware/go-vmware-nsxt/manager"
 )
 
+const managerBasePath = "/api/v1"
+
 type nsxtClient struct {
-	apiClient *nsxt.APIClient
+	apiClient *APIClient
 	logger    log.Logger
 }
 
-func NewNSXTClient(apiClient *nsxt.APIClient, logger log.Logger) *nsxtClient {
+func NewNSXTClient(apiClient *APIClient, logger log.Logger) *nsxtClient {
 	return &nsxtClient{
 		apiClient: apiClient,
 		logger:    logger,
 	}
 }
 
-func (c *nsxtClient) ListAllLogicalRouters() ([]manager.LogicalRouter, error) {
+// call runs the API request fn with ctx and returns as soon as ctx is done.
+// The generated client does not attach the context to its HTTP requests, so
+// fn keeps running in the background until the HTTP client gives up, but no
+// further request is sent once ctx is done. The credentials of the API client
+// are copied into the context passed to fn.
+func (c *nsxtClient) call(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if auth, ok := c.apiClient.Context.Value(nsxt.ContextBasicAuth).(nsxt.BasicAuth); ok {
+		ctx = context.WithValue(ctx, nsxt.ContextBasicAuth, auth)
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fn(ctx)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *nsxtClient) ListAllLogicalRouters(ctx context.Context) ([]manager.LogicalRouter, error) {
 	var logicalRouters []manager.LogicalRouter
 	var cursor string
 	for {
 		localVarOptionals := make(map[string]interface{})
 		localVarOptionals["cursor"] = cursor
-		logicalRoutersResult, _, err := c.apiClient.LogicalRoutingAndServicesApi.ListLogicalRouters(c.apiClient.Context, localVarOptionals)
+		var logicalRoutersResult manager.LogicalRouterListResult
+		err := c.call(ctx, func(ctx context.Context) (err error) {
+			logicalRoutersResult, _, err = c.apiClient.LogicalRoutingAndServicesApi.ListLogicalRouters(ctx, localVarOptionals)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -39,18 +73,29 @@ func (c *nsxtClient) ListAllLogicalRouters() ([]manager.LogicalRouter, error) {
 	return logicalRouters, nil
 }
 
-func (c *nsxtClient) GetLogicalRouterStatus(lrouterID string) (manager.LogicalRouterStatus, error) {
-	lrouterStatus, _, err := c.apiClient.LogicalRoutingAndServicesApi.GetLogicalRouterStatus(c.apiClient.Context, lrouterID, nil)
-	return lrouterStatus, err
+func (c *nsxtClient) GetLogicalRouterStatus(ctx context.Context, lrouterID string) (manager.LogicalRouterStatus, error) {
+	var lrouterStatus manager.LogicalRouterStatus
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		lrouterStatus, _, err = c.apiClient.LogicalRoutingAndServicesApi.GetLogicalRouterStatus(ctx, lrouterID, nil)
+		return err
+	})
+	if err != nil {
+		return manager.LogicalRouterStatus{}, err
+	}
+	return lrouterStatus, nil
 }
 
-func (c *nsxtClient) ListAllNatRules(lrouterID string) ([]manager.NatRule, error) {
+func (c *nsxtClient) ListAllNatRules(ctx context.Context, lrouterID string) ([]manager.NatRule, error) {
 	var natRules []manager.NatRule
 	var cursor string
 	for {
 		localVarOptionals := make(map[string]interface{})
 		localVarOptionals["cursor"] = cursor
-		natRulesResult, _, err := c.apiClient.LogicalRoutingAndServicesApi.ListNatRules(c.apiClient.Context, lrouterID, localVarOptionals)
+		var natRulesResult manager.NatRuleListResult
+		err := c.call(ctx, func(ctx context.Context) (err error) {
+			natRulesResult, _, err = c.apiClient.LogicalRoutingAndServicesApi.ListNatRules(ctx, lrouterID, localVarOptionals)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -63,30 +108,145 @@ func (c *nsxtClient) ListAllNatRules(lrouterID string) ([]manager.NatRule, error
 	return natRules, nil
 }
 
-func (c *nsxtClient) GetNatStatisticsPerRule(lrouterID, ruleID string) (manager.NatStatisticsPerRule, error) {
+func (c *nsxtClient) GetNatStatisticsPerRule(ctx context.Context, lrouterID, ruleID string) (manager.NatStatisticsPerRule, error) {
 	localVarOptionals := make(map[string]interface{})
 	localVarOptionals["source"] = "realtime"
-	natStatsResult, _, err := c.apiClient.LogicalRoutingAndServicesApi.GetNatStatisticsPerRule(c.apiClient.Context, lrouterID, ruleID, localVarOptionals)
-	return natStatsResult, err
+	var natStatsResult manager.NatStatisticsPerRule
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		natStatsResult, _, err = c.apiClient.LogicalRoutingAndServicesApi.GetNatStatisticsPerRule(ctx, lrouterID, ruleID, localVarOptionals)
+		return err
+	})
+	if err != nil {
+		return manager.NatStatisticsPerRule{}, err
+	}
+	return natStatsResult, nil
 }
 
-func (c *nsxtClient) ListLogicalPorts(localVarOptionals map[string]interface{}) (manager.LogicalPortListResult, error) {
-	lportsResult, _, err := c.apiClient.LogicalSwitchingApi.ListLogicalPorts(c.apiClient.Context, localVarOptionals)
-	return lportsResult, err
+func (c *nsxtClient) ListAllBgpNeighborsStatus(ctx context.Context, lrouterID string) ([]manager.BgpNeighborStatus, error) {
+	var bgpNeighborsStatus []manager.BgpNeighborStatus
+	var cursor string
+	for {
+		localVarOptionals := make(map[string]interface{})
+		localVarOptionals["cursor"] = cursor
+		var bgpNeighborsStatusResult manager.BgpNeighborsStatusListResult
+		err := c.call(ctx, func(ctx context.Context) (err error) {
+			bgpNeighborsStatusResult, _, err = c.apiClient.LogicalRoutingAndServicesApi.GetBgpNeighborsStatus(ctx, lrouterID, localVarOptionals)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		bgpNeighborsStatus = append(bgpNeighborsStatus, bgpNeighborsStatusResult.Results...)
+		cursor = bgpNeighborsStatusResult.Cursor
+		if len(cursor) == 0 {
+			break
+		}
+	}
+	return bgpNeighborsStatus, nil
 }
 
-func (c *nsxtClient) GetLogicalPortOperationalStatus(lportId string, localVarOptionals map[string]interface{}) (manager.LogicalPortOperationalStatus, error) {
-	lportStatus, _, err := c.apiClient.LogicalSwitchingApi.GetLogicalPortOperationalStatus(c.apiClient.Context, lportId, localVarOptionals)
-	return lportStatus, err
+func (c *nsxtClient) ListAllRoutingTableRoutes(ctx context.Context, lrouterID, transportNodeID string) ([]manager.LogicalRouterRouteEntry, error) {
+	var routes []manager.LogicalRouterRouteEntry
+	var cursor string
+	for {
+		localVarOptionals := make(map[string]interface{})
+		localVarOptionals["cursor"] = cursor
+		var routeTable manager.LogicalRouterRouteTable
+		err := c.call(ctx, func(ctx context.Context) (err error) {
+			routeTable, _, err = c.apiClient.LogicalRoutingAndServicesApi.GetLogicalRouterRoutingTable(ctx, lrouterID, transportNodeID, localVarOptionals)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, routeTable.Results...)
+		cursor = routeTable.Cursor
+		if len(cursor) == 0 {
+			break
+		}
+	}
+	return routes, nil
 }
 
-func (c *nsxtClient) ListAllLogicalRouterPorts() ([]manager.LogicalRouterPort, error) {
+func (c *nsxtClient) ListAllForwardingTableRoutes(ctx context.Context, lrouterID, transportNodeID string) ([]manager.LogicalRouterRouteEntry, error) {
+	var routes []manager.LogicalRouterRouteEntry
+	var cursor string
+	for {
+		localVarOptionals := make(map[string]interface{})
+		localVarOptionals["cursor"] = cursor
+		var routeTable manager.LogicalRouterRouteTable
+		err := c.call(ctx, func(ctx context.Context) (err error) {
+			routeTable, _, err = c.apiClient.LogicalRoutingAndServicesApi.GetLogicalRouterForwardingTable(ctx, lrouterID, transportNodeID, localVarOptionals)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, routeTable.Results...)
+		cursor = routeTable.Cursor
+		if len(cursor) == 0 {
+			break
+		}
+	}
+	return routes, nil
+}
+
+// ListAllBfdPeersStatus returns the BFD sessions of a logical router on a
+// transport node. The generated client does not cover the API.
+func (c *nsxtClient) ListAllBfdPeersStatus(ctx context.Context, lrouterID, transportNodeID string) ([]routing.BfdPeerStatus, error) {
+	var bfdPeersStatus []routing.BfdPeerStatus
+	var cursor string
+	for {
+		query := cursorQuery(cursor)
+		query.Set("transport_node_id", transportNodeID)
+		var bfdPeersStatusResult routing.BfdPeerStatusListResult
+		if err := c.apiClient.get(ctx, managerBasePath+"/logical-routers/"+lrouterID+"/routing/bfd-peers/status", query, &bfdPeersStatusResult); err != nil {
+			return nil, err
+		}
+		bfdPeersStatus = append(bfdPeersStatus, bfdPeersStatusResult.Results...)
+		cursor = bfdPeersStatusResult.Cursor
+		if len(cursor) == 0 {
+			break
+		}
+	}
+	return bfdPeersStatus, nil
+}
+
+func (c *nsxtClient) ListLogicalPorts(ctx context.Context, localVarOptionals map[string]interface{}) (manager.LogicalPortListResult, error) {
+	var lportsResult manager.LogicalPortListResult
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		lportsResult, _, err = c.apiClient.LogicalSwitchingApi.ListLogicalPorts(ctx, localVarOptionals)
+		return err
+	})
+	if err != nil {
+		return manager.LogicalPortListResult{}, err
+	}
+	return lportsResult, nil
+}
+
+func (c *nsxtClient) GetLogicalPortOperationalStatus(ctx context.Context, lportId string, localVarOptionals map[string]interface{}) (manager.LogicalPortOperationalStatus, error) {
+	var lportStatus manager.LogicalPortOperationalStatus
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		lportStatus, _, err = c.apiClient.LogicalSwitchingApi.GetLogicalPortOperationalStatus(ctx, lportId, localVarOptionals)
+		return err
+	})
+	if err != nil {
+		return manager.LogicalPortOperationalStatus{}, err
+	}
+	return lportStatus, nil
+}
+
+func (c *nsxtClient) ListAllLogicalRouterPorts(ctx context.Context) ([]manager.LogicalRouterPort, error) {
 	var logicalRouterPorts []manager.LogicalRouterPort
 	var cursor string
 	for {
 		localVarOptionals := make(map[string]interface{})
 		localVarOptionals["cursor"] = cursor
-		logicalRouterPortsResult, _, err := c.apiClient.LogicalRoutingAndServicesApi.ListLogicalRouterPorts(c.apiClient.Context, localVarOptionals)
+		var logicalRouterPortsResult manager.LogicalRouterPortListResult
+		err := c.call(ctx, func(ctx context.Context) (err error) {
+			logicalRouterPortsResult, _, err = c.apiClient.LogicalRoutingAndServicesApi.ListLogicalRouterPorts(ctx, localVarOptionals)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -99,18 +259,29 @@ func (c *nsxtClient) ListAllLogicalRouterPorts() ([]manager.LogicalRouterPort, e
 	return logicalRouterPorts, nil
 }
 
-func (c *nsxtClient) GetLogicalRouterPortStatisticsSummary(lrportID string) (manager.LogicalRouterPortStatisticsSummary, error) {
-	lrportsStatus, _, err := c.apiClient.LogicalRoutingAndServicesApi.GetLogicalRouterPortStatisticsSummary(c.apiClient.Context, lrportID, nil)
-	return lrportsStatus, err
+func (c *nsxtClient) GetLogicalRouterPortStatisticsSummary(ctx context.Context, lrportID string) (manager.LogicalRouterPortStatisticsSummary, error) {
+	var lrportsStatus manager.LogicalRouterPortStatisticsSummary
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		lrportsStatus, _, err = c.apiClient.LogicalRoutingAndServicesApi.GetLogicalRouterPortStatisticsSummary(ctx, lrportID, nil)
+		return err
+	})
+	if err != nil {
+		return manager.LogicalRouterPortStatisticsSummary{}, err
+	}
+	return lrportsStatus, nil
 }
 
-func (c *nsxtClient) ListAllDHCPServers() ([]manager.LogicalDhcpServer, error) {
+func (c *nsxtClient) ListAllDHCPServers(ctx context.Context) ([]manager.LogicalDhcpServer, error) {
 	var dhcps []manager.LogicalDhcpServer
 	var cursor string
 	for {
 		localVarOptionals := make(map[string]interface{})
 		localVarOptionals["cursor"] = cursor
-		dhcpListResponse, _, err := c.apiClient.ServicesApi.ListDhcpServers(c.apiClient.Context, localVarOptionals)
+		var dhcpListResponse manager.LogicalDhcpServerListResult
+		err := c.call(ctx, func(ctx context.Context) (err error) {
+			dhcpListResponse, _, err = c.apiClient.ServicesApi.ListDhcpServers(ctx, localVarOptionals)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -123,23 +294,41 @@ func (c *nsxtClient) ListAllDHCPServers() ([]manager.LogicalDhcpServer, error) {
 	return dhcps, nil
 }
 
-func (c *nsxtClient) GetDhcpStatus(dhcpID string, localVarOptionals map[string]interface{}) (manager.DhcpServerStatus, error) {
-	dhcpServerStatus, _, err := c.apiClient.ServicesApi.GetDhcpStatus(c.apiClient.Context, dhcpID)
-	return dhcpServerStatus, err
+func (c *nsxtClient) GetDhcpStatus(ctx context.Context, dhcpID string, localVarOptionals map[string]interface{}) (manager.DhcpServerStatus, error) {
+	var dhcpServerStatus manager.DhcpServerStatus
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		dhcpServerStatus, _, err = c.apiClient.ServicesApi.GetDhcpStatus(ctx, dhcpID)
+		return err
+	})
+	if err != nil {
+		return manager.DhcpServerStatus{}, err
+	}
+	return dhcpServerStatus, nil
 }
 
-func (c *nsxtClient) GetDHCPStatistic(dhcpID string) (manager.DhcpStatistics, error) {
-	dhcpServerStatistic, _, err := c.apiClient.ServicesApi.GetDhcpStatistics(c.apiClient.Context, dhcpID)
-	return dhcpServerStatistic, err
+func (c *nsxtClient) GetDHCPStatistic(ctx context.Context, dhcpID string) (manager.DhcpStatistics, error) {
+	var dhcpServerStatistic manager.DhcpStatistics
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		dhcpServerStatistic, _, err = c.apiClient.ServicesApi.GetDhcpStatistics(ctx, dhcpID)
+		return err
+	})
+	if err != nil {
+		return manager.DhcpStatistics{}, err
+	}
+	return dhcpServerStatistic, nil
 }
 
-func (c *nsxtClient) ListAllTransportNodes() ([]manager.TransportNode, error) {
+func (c *nsxtClient) ListAllTransportNodes(ctx context.Context) ([]manager.TransportNode, error) {
 	var transportNodes []manager.TransportNode
 	var cursor string
 	for {
 		localVarOptionals := make(map[string]interface{})
 		localVarOptionals["cursor"] = cursor
-		transportNodesResult, _, err := c.apiClient.NetworkTransportApi.ListTransportNodes(c.apiClient.Context, localVarOptionals)
+		var transportNodesResult manager.TransportNodeListResult
+		err := c.call(ctx, func(ctx context.Context) (err error) {
+			transportNodesResult, _, err = c.apiClient.NetworkTransportApi.ListTransportNodes(ctx, localVarOptionals)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -152,18 +341,29 @@ func (c *nsxtClient) ListAllTransportNodes() ([]manager.TransportNode, error) {
 	return transportNodes, nil
 }
 
-func (c *nsxtClient) GetTransportNodeStatus(nodeID string) (manager.TransportNodeStatus, error) {
-	transportNodeStatus, _, err := c.apiClient.TroubleshootingAndMonitoringApi.GetTransportNodeStatus(c.apiClient.Context, nodeID, nil)
-	return transportNodeStatus, err
+func (c *nsxtClient) GetTransportNodeStatus(ctx context.Context, nodeID string) (manager.TransportNodeStatus, error) {
+	var transportNodeStatus manager.TransportNodeStatus
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		transportNodeStatus, _, err = c.apiClient.TroubleshootingAndMonitoringApi.GetTransportNodeStatus(ctx, nodeID, nil)
+		return err
+	})
+	if err != nil {
+		return manager.TransportNodeStatus{}, err
+	}
+	return transportNodeStatus, nil
 }
 
-func (c *nsxtClient) ListAllEdgeClusters() ([]manager.EdgeCluster, error) {
+func (c *nsxtClient) ListAllEdgeClusters(ctx context.Context) ([]manager.EdgeCluster, error) {
 	var edgeClusters []manager.EdgeCluster
 	var cursor string
 	for {
 		localVarOptionals := make(map[string]interface{})
 		localVarOptionals["cursor"] = cursor
-		res, _, err := c.apiClient.NetworkTransportApi.ListEdgeClusters(c.apiClient.Context, localVarOptionals)
+		var res manager.EdgeClusterListResult
+		err := c.call(ctx, func(ctx context.Context) (err error) {
+			res, _, err = c.apiClient.NetworkTransportApi.ListEdgeClusters(ctx, localVarOptionals)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -176,28 +376,53 @@ func (c *nsxtClient) ListAllEdgeClusters() ([]manager.EdgeCluster, error) {
 	return edgeClusters, nil
 }
 
-func (c *nsxtClient) ReadClusterStatus() (administration.ClusterStatus, error) {
-	clusterStatus, _, err := c.apiClient.NsxComponentAdministrationApi.ReadClusterStatus(c.apiClient.Context, nil)
-	return clusterStatus, err
+func (c *nsxtClient) ReadClusterStatus(ctx context.Context) (administration.ClusterStatus, error) {
+	var clusterStatus administration.ClusterStatus
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		clusterStatus, _, err = c.apiClient.NsxComponentAdministrationApi.ReadClusterStatus(ctx, nil)
+		return err
+	})
+	if err != nil {
+		return administration.ClusterStatus{}, err
+	}
+	return clusterStatus, nil
 }
 
-func (c *nsxtClient) ReadClusterNodesAggregateStatus() (administration.ClustersAggregateInfo, error) {
-	clusterNodesStatus, _, err := c.apiClient.NsxComponentAdministrationApi.ReadClusterNodesAggregateStatus(c.apiClient.Context)
-	return clusterNodesStatus, err
+func (c *nsxtClient) ReadClusterNodesAggregateStatus(ctx context.Context) (administration.ClustersAggregateInfo, error) {
+	var clusterNodesStatus administration.ClustersAggregateInfo
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		clusterNodesStatus, _, err = c.apiClient.NsxComponentAdministrationApi.ReadClusterNodesAggregateStatus(ctx)
+		return err
+	})
+	if err != nil {
+		return administration.ClustersAggregateInfo{}, err
+	}
+	return clusterNodesStatus, nil
 }
 
-func (c *nsxtClient) ReadApplianceManagementServiceStatus() (administration.NodeServiceStatusProperties, error) {
-	applianceServiceStatus, _, err := c.apiClient.NsxComponentAdministrationApi.ReadApplianceManagementServiceStatus(c.apiClient.Context)
-	return applianceServiceStatus, err
+func (c *nsxtClient) ReadApplianceManagementServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
+	var applianceServiceStatus administration.NodeServiceStatusProperties
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		applianceServiceStatus, _, err = c.apiClient.NsxComponentAdministrationApi.ReadApplianceManagementServiceStatus(ctx)
+		return err
+	})
+	if err != nil {
+		return administration.NodeServiceStatusProperties{}, err
+	}
+	return applianceServiceStatus, nil
 }
 
-func (c *nsxtClient) ListAllLogicalSwitches() ([]manager.LogicalSwitch, error) {
+func (c *nsxtClient) ListAllLogicalSwitches(ctx context.Context) ([]manager.LogicalSwitch, error) {
 	var logicalSwitches []manager.LogicalSwitch
 	var cursor string
 	for {
 		localVarOptionals := make(map[string]interface{})
 		localVarOptionals["cursor"] = cursor
-		logicalSwitchListResult, _, err := c.apiClient.LogicalSwitchingApi.ListLogicalSwitches(c.apiClient.Context, localVarOptionals)
+		var logicalSwitchListResult manager.LogicalSwitchListResult
+		err := c.call(ctx, func(ctx context.Context) (err error) {
+			logicalSwitchListResult, _, err = c.apiClient.LogicalSwitchingApi.ListLogicalSwitches(ctx, localVarOptionals)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -210,78 +435,173 @@ func (c *nsxtClient) ListAllLogicalSwitches() ([]manager.LogicalSwitch, error) {
 	return logicalSwitches, nil
 }
 
-func (c *nsxtClient) ReadNSXMessageBusServiceStatus() (administration.NodeServiceStatusProperties, error) {
-	messageBusServiceStatus, _, err := c.apiClient.NsxComponentAdministrationApi.ReadNSXMessageBusServiceStatus(c.apiClient.Context)
-	return messageBusServiceStatus, err
+func (c *nsxtClient) ReadNSXMessageBusServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
+	var messageBusServiceStatus administration.NodeServiceStatusProperties
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		messageBusServiceStatus, _, err = c.apiClient.NsxComponentAdministrationApi.ReadNSXMessageBusServiceStatus(ctx)
+		return err
+	})
+	if err != nil {
+		return administration.NodeServiceStatusProperties{}, err
+	}
+	return messageBusServiceStatus, nil
 }
 
-func (c *nsxtClient) ReadNTPServiceStatus() (administration.NodeServiceStatusProperties, error) {
-	ntpServiceStatus, _, err := c.apiClient.NsxComponentAdministrationApi.ReadNSXMessageBusServiceStatus(c.apiClient.Context)
-	return ntpServiceStatus, err
+func (c *nsxtClient) ReadNTPServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
+	var ntpServiceStatus administration.NodeServiceStatusProperties
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		ntpServiceStatus, _, err = c.apiClient.NsxComponentAdministrationApi.ReadNSXMessageBusServiceStatus(ctx)
+		return err
+	})
+	if err != nil {
+		return administration.NodeServiceStatusProperties{}, err
+	}
+	return ntpServiceStatus, nil
 }
 
-func (c *nsxtClient) ReadNsxUpgradeAgentServiceStatus() (administration.NodeServiceStatusProperties, error) {
-	upgradeAgentServiceStatus, _, err := c.apiClient.NsxComponentAdministrationApi.ReadNsxUpgradeAgentServiceStatus(c.apiClient.Context)
-	return upgradeAgentServiceStatus, err
+func (c *nsxtClient) ReadNsxUpgradeAgentServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
+	var upgradeAgentServiceStatus administration.NodeServiceStatusProperties
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		upgradeAgentServiceStatus, _, err = c.apiClient.NsxComponentAdministrationApi.ReadNsxUpgradeAgentServiceStatus(ctx)
+		return err
+	})
+	if err != nil {
+		return administration.NodeServiceStatusProperties{}, err
+	}
+	return upgradeAgentServiceStatus, nil
 }
 
-func (c *nsxtClient) ReadProtonServiceStatus() (administration.NodeServiceStatusProperties, error) {
-	protonServiceStatus, _, err := c.apiClient.NsxComponentAdministrationApi.ReadProtonServiceStatus(c.apiClient.Context)
-	return protonServiceStatus, err
+func (c *nsxtClient) ReadProtonServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
+	var protonServiceStatus administration.NodeServiceStatusProperties
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		protonServiceStatus, _, err = c.apiClient.NsxComponentAdministrationApi.ReadProtonServiceStatus(ctx)
+		return err
+	})
+	if err != nil {
+		return administration.NodeServiceStatusProperties{}, err
+	}
+	return protonServiceStatus, nil
 }
 
-func (c *nsxtClient) ReadProxyServiceStatus() (administration.NodeServiceStatusProperties, error) {
-	proxyServiceStatus, _, err := c.apiClient.NsxComponentAdministrationApi.ReadProxyServiceStatus(c.apiClient.Context)
-	return proxyServiceStatus, err
+func (c *nsxtClient) ReadProxyServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
+	var proxyServiceStatus administration.NodeServiceStatusProperties
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		proxyServiceStatus, _, err = c.apiClient.NsxComponentAdministrationApi.ReadProxyServiceStatus(ctx)
+		return err
+	})
+	if err != nil {
+		return administration.NodeServiceStatusProperties{}, err
+	}
+	return proxyServiceStatus, nil
 }
 
-func (c *nsxtClient) ReadRabbitMQServiceStatus() (administration.NodeServiceStatusProperties, error) {
-	rabbbitMQServiceStatus, _, err := c.apiClient.NsxComponentAdministrationApi.ReadRabbitMQServiceStatus(c.apiClient.Context)
-	return rabbbitMQServiceStatus, err
+func (c *nsxtClient) ReadRabbitMQServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
+	var rabbbitMQServiceStatus administration.NodeServiceStatusProperties
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		rabbbitMQServiceStatus, _, err = c.apiClient.NsxComponentAdministrationApi.ReadRabbitMQServiceStatus(ctx)
+		return err
+	})
+	if err != nil {
+		return administration.NodeServiceStatusProperties{}, err
+	}
+	return rabbbitMQServiceStatus, nil
 }
 
-func (c *nsxtClient) ReadRepositoryServiceStatus() (administration.NodeServiceStatusProperties, error) {
-	repositoryServiceStatus, _, err := c.apiClient.NsxComponentAdministrationApi.ReadRepositoryServiceStatus(c.apiClient.Context)
-	return repositoryServiceStatus, err
+func (c *nsxtClient) ReadRepositoryServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
+	var repositoryServiceStatus administration.NodeServiceStatusProperties
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		repositoryServiceStatus, _, err = c.apiClient.NsxComponentAdministrationApi.ReadRepositoryServiceStatus(ctx)
+		return err
+	})
+	if err != nil {
+		return administration.NodeServiceStatusProperties{}, err
+	}
+	return repositoryServiceStatus, nil
 }
 
-func (c *nsxtClient) ReadSNMPServiceStatus() (administration.NodeServiceStatusProperties, error) {
-	snmpServiceStatus, _, err := c.apiClient.NsxComponentAdministrationApi.ReadSNMPServiceStatus(c.apiClient.Context)
-	return snmpServiceStatus, err
+func (c *nsxtClient) ReadSNMPServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
+	var snmpServiceStatus administration.NodeServiceStatusProperties
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		snmpServiceStatus, _, err = c.apiClient.NsxComponentAdministrationApi.ReadSNMPServiceStatus(ctx)
+		return err
+	})
+	if err != nil {
+		return administration.NodeServiceStatusProperties{}, err
+	}
+	return snmpServiceStatus, nil
 }
 
-func (c *nsxtClient) ReadSSHServiceStatus() (administration.NodeServiceStatusProperties, error) {
-	sshServiceStatus, _, err := c.apiClient.NsxComponentAdministrationApi.ReadSSHServiceStatus(c.apiClient.Context)
-	return sshServiceStatus, err
+func (c *nsxtClient) ReadSSHServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
+	var sshServiceStatus administration.NodeServiceStatusProperties
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		sshServiceStatus, _, err = c.apiClient.NsxComponentAdministrationApi.ReadSSHServiceStatus(ctx)
+		return err
+	})
+	if err != nil {
+		return administration.NodeServiceStatusProperties{}, err
+	}
+	return sshServiceStatus, nil
 }
 
-func (c *nsxtClient) ReadSearchServiceStatus() (administration.NodeServiceStatusProperties, error) {
-	searchServiceStatus, _, err := c.apiClient.NsxComponentAdministrationApi.ReadSearchServiceStatus(c.apiClient.Context)
-	return searchServiceStatus, err
+func (c *nsxtClient) ReadSearchServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
+	var searchServiceStatus administration.NodeServiceStatusProperties
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		searchServiceStatus, _, err = c.apiClient.NsxComponentAdministrationApi.ReadSearchServiceStatus(ctx)
+		return err
+	})
+	if err != nil {
+		return administration.NodeServiceStatusProperties{}, err
+	}
+	return searchServiceStatus, nil
 }
 
-func (c *nsxtClient) ReadSyslogServiceStatus() (administration.NodeServiceStatusProperties, error) {
-	syslogServiceStatus, _, err := c.apiClient.NsxComponentAdministrationApi.ReadSyslogServiceStatus(c.apiClient.Context)
-	return syslogServiceStatus, err
+func (c *nsxtClient) ReadSyslogServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
+	var syslogServiceStatus administration.NodeServiceStatusProperties
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		syslogServiceStatus, _, err = c.apiClient.NsxComponentAdministrationApi.ReadSyslogServiceStatus(ctx)
+		return err
+	})
+	if err != nil {
+		return administration.NodeServiceStatusProperties{}, err
+	}
+	return syslogServiceStatus, nil
 }
 
-func (c *nsxtClient) GetLogicalSwitchState(lswitchID string) (manager.LogicalSwitchState, error) {
-	logicalSwitchesStatus, _, err := c.apiClient.LogicalSwitchingApi.GetLogicalSwitchState(c.apiClient.Context, lswitchID)
-	return logicalSwitchesStatus, err
+func (c *nsxtClient) GetLogicalSwitchState(ctx context.Context, lswitchID string) (manager.LogicalSwitchState, error) {
+	var logicalSwitchesStatus manager.LogicalSwitchState
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		logicalSwitchesStatus, _, err = c.apiClient.LogicalSwitchingApi.GetLogicalSwitchState(ctx, lswitchID)
+		return err
+	})
+	if err != nil {
+		return manager.LogicalSwitchState{}, err
+	}
+	return logicalSwitchesStatus, nil
 }
 
-func (c *nsxtClient) GetLogicalSwitchStatistic(lswitchID string) (manager.LogicalSwitchStatistics, error) {
-	logicalSwitchStatistic, _, err := c.apiClient.LogicalSwitchingApi.GetLogicalSwitchStatistics(c.apiClient.Context, lswitchID, nil)
-	return logicalSwitchStatistic, err
+func (c *nsxtClient) GetLogicalSwitchStatistic(ctx context.Context, lswitchID string) (manager.LogicalSwitchStatistics, error) {
+	var logicalSwitchStatistic manager.LogicalSwitchStatistics
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		logicalSwitchStatistic, _, err = c.apiClient.LogicalSwitchingApi.GetLogicalSwitchStatistics(ctx, lswitchID, nil)
+		return err
+	})
+	if err != nil {
+		return manager.LogicalSwitchStatistics{}, err
+	}
+	return logicalSwitchStatistic, nil
 }
 
-func (c *nsxtClient) ListAllLoadBalancers() ([]loadbalancer.LbService, error) {
+func (c *nsxtClient) ListAllLoadBalancers(ctx context.Context) ([]loadbalancer.LbService, error) {
 	var loadBalancers []loadbalancer.LbService
 	var cursor string
 	for {
 		localVarOptionals := make(map[string]interface{})
 		localVarOptionals["cursor"] = cursor
-		lbServiceListResult, _, err := c.apiClient.ServicesApi.ListLoadBalancerServices(c.apiClient.Context, localVarOptionals)
+		var lbServiceListResult loadbalancer.LbServiceListResult
+		err := c.call(ctx, func(ctx context.Context) (err error) {
+			lbServiceListResult, _, err = c.apiClient.ServicesApi.ListLoadBalancerServices(ctx, localVarOptionals)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -294,25 +614,43 @@ func (c *nsxtClient) ListAllLoadBalancers() ([]loadbalancer.LbService, error) {
 	return loadBalancers, nil
 }
 
-func (c *nsxtClient) GetLoadBalancerStatus(loadBalancerID string) (loadbalancer.LbServiceStatus, error) {
-	loadBalancerStatus, _, err := c.apiClient.ServicesApi.ReadLoadBalancerServiceStatus(c.apiClient.Context, loadBalancerID, nil)
-	return loadBalancerStatus, err
+func (c *nsxtClient) GetLoadBalancerStatus(ctx context.Context, loadBalancerID string) (loadbalancer.LbServiceStatus, error) {
+	var loadBalancerStatus loadbalancer.LbServiceStatus
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		loadBalancerStatus, _, err = c.apiClient.ServicesApi.ReadLoadBalancerServiceStatus(ctx, loadBalancerID, nil)
+		return err
+	})
+	if err != nil {
+		return loadbalancer.LbServiceStatus{}, err
+	}
+	return loadBalancerStatus, nil
 }
 
-func (c *nsxtClient) GetLoadBalancerStatistic(loadBalancerID string) (loadbalancer.LbServiceStatistics, error) {
+func (c *nsxtClient) GetLoadBalancerStatistic(ctx context.Context, loadBalancerID string) (loadbalancer.LbServiceStatistics, error) {
 	localVarOptionals := make(map[string]interface{})
 	localVarOptionals["source"] = "realtime"
-	loadBalancerStatistic, _, err := c.apiClient.ServicesApi.ReadLoadBalancerServiceStatistics(c.apiClient.Context, loadBalancerID, localVarOptionals)
-	return loadBalancerStatistic, err
+	var loadBalancerStatistic loadbalancer.LbServiceStatistics
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		loadBalancerStatistic, _, err = c.apiClient.ServicesApi.ReadLoadBalancerServiceStatistics(ctx, loadBalancerID, localVarOptionals)
+		return err
+	})
+	if err != nil {
+		return loadbalancer.LbServiceStatistics{}, err
+	}
+	return loadBalancerStatistic, nil
 }
 
-func (c *nsxtClient) ListAllFirewallSections() ([]manager.FirewallSection, error) {
+func (c *nsxtClient) ListAllFirewallSections(ctx context.Context) ([]manager.FirewallSection, error) {
 	var firewallSections []manager.FirewallSection
 	var cursor string
 	for {
 		localVarOptionals := make(map[string]interface{})
 		localVarOptionals["cursor"] = cursor
-		firewallSectionsResult, _, err := c.apiClient.ServicesApi.ListSections(c.apiClient.Context, localVarOptionals)
+		var firewallSectionsResult manager.FirewallSectionListResult
+		err := c.call(ctx, func(ctx context.Context) (err error) {
+			firewallSectionsResult, _, err = c.apiClient.ServicesApi.ListSections(ctx, localVarOptionals)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -325,13 +663,17 @@ func (c *nsxtClient) ListAllFirewallSections() ([]manager.FirewallSection, error
 	return firewallSections, nil
 }
 
-func (c *nsxtClient) GetAllFirewallRules(sectionID string) ([]manager.FirewallRule, error) {
+func (c *nsxtClient) GetAllFirewallRules(ctx context.Context, sectionID string) ([]manager.FirewallRule, error) {
 	var firewallRules []manager.FirewallRule
 	var cursor string
 	for {
 		localVarOptionals := make(map[string]interface{})
 		localVarOptionals["cursor"] = cursor
-		firewallRulesResult, _, err := c.apiClient.ServicesApi.GetRules(c.apiClient.Context, sectionID, localVarOptionals)
+		var firewallRulesResult manager.FirewallRuleListResult
+		err := c.call(ctx, func(ctx context.Context) (err error) {
+			firewallRulesResult, _, err = c.apiClient.ServicesApi.GetRules(ctx, sectionID, localVarOptionals)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -344,7 +686,101 @@ func (c *nsxtClient) GetAllFirewallRules(sectionID string) ([]manager.FirewallRu
 	return firewallRules, nil
 }
 
-func (c *nsxtClient) GetFirewallStats(sectionID, ruleID string) (manager.FirewallStats, error) {
-	firewallStats, _, err := c.apiClient.ServicesApi.GetFirewallStats(c.apiClient.Context, sectionID, ruleID, nil)
-	return firewallStats, err
+func (c *nsxtClient) GetFirewallStats(ctx context.Context, sectionID, ruleID string) (manager.FirewallStats, error) {
+	var firewallStats manager.FirewallStats
+	err := c.call(ctx, func(ctx context.Context) (err error) {
+		firewallStats, _, err = c.apiClient.ServicesApi.GetFirewallStats(ctx, sectionID, ruleID, nil)
+		return err
+	})
+	if err != nil {
+		return manager.FirewallStats{}, err
+	}
+	return firewallStats, nil
+}
+
+// ListAllIPSecVPNSessions returns the IPSec VPN sessions. The generated client
+// does not cover the VPN API.
+func (c *nsxtClient) ListAllIPSecVPNSessions(ctx context.Context) ([]vpn.IPSecVPNSession, error) {
+	var sessions []vpn.IPSecVPNSession
+	var cursor string
+	for {
+		var sessionsResult vpn.IPSecVPNSessionListResult
+		if err := c.apiClient.get(ctx, managerBasePath+"/vpn/ipsec/sessions", cursorQuery(cursor), &sessionsResult); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, sessionsResult.Results...)
+		cursor = sessionsResult.Cursor
+		if len(cursor) == 0 {
+			break
+		}
+	}
+	return sessions, nil
+}
+
+func (c *nsxtClient) ListAllIPSecVPNPeerEndpoints(ctx context.Context) ([]vpn.IPSecVPNPeerEndpoint, error) {
+	var peerEndpoints []vpn.IPSecVPNPeerEndpoint
+	var cursor string
+	for {
+		var peerEndpointsResult vpn.IPSecVPNPeerEndpointListResult
+		if err := c.apiClient.get(ctx, managerBasePath+"/vpn/ipsec/peer-endpoints", cursorQuery(cursor), &peerEndpointsResult); err != nil {
+			return nil, err
+		}
+		peerEndpoints = append(peerEndpoints, peerEndpointsResult.Results...)
+		cursor = peerEndpointsResult.Cursor
+		if len(cursor) == 0 {
+			break
+		}
+	}
+	return peerEndpoints, nil
+}
+
+func (c *nsxtClient) GetIPSecVPNSessionStatus(ctx context.Context, sessionID string) (vpn.IPSecVPNSessionStatus, error) {
+	var sessionStatus vpn.IPSecVPNSessionStatus
+	if err := c.apiClient.get(ctx, managerBasePath+"/vpn/ipsec/sessions/"+sessionID+"/status", nil, &sessionStatus); err != nil {
+		return vpn.IPSecVPNSessionStatus{}, err
+	}
+	return sessionStatus, nil
+}
+
+func (c *nsxtClient) GetIPSecVPNSessionStatistics(ctx context.Context, sessionID string) (vpn.IPSecVPNSessionStatistics, error) {
+	var sessionStatistics vpn.IPSecVPNSessionStatistics
+	if err := c.apiClient.get(ctx, managerBasePath+"/vpn/ipsec/sessions/"+sessionID+"/statistics", nil, &sessionStatistics); err != nil {
+		return vpn.IPSecVPNSessionStatistics{}, err
+	}
+	return sessionStatistics, nil
+}
+
+// ListAllL2VPNSessions returns the L2VPN sessions. The generated client does
+// not cover the VPN API.
+func (c *nsxtClient) ListAllL2VPNSessions(ctx context.Context) ([]vpn.L2VPNSession, error) {
+	var sessions []vpn.L2VPNSession
+	var cursor string
+	for {
+		var sessionsResult vpn.L2VPNSessionListResult
+		if err := c.apiClient.get(ctx, managerBasePath+"/vpn/l2vpn/sessions", cursorQuery(cursor), &sessionsResult); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, sessionsResult.Results...)
+		cursor = sessionsResult.Cursor
+		if len(cursor) == 0 {
+			break
+		}
+	}
+	return sessions, nil
+}
+
+func (c *nsxtClient) GetL2VPNSessionStatus(ctx context.Context, sessionID string) (vpn.L2VPNSessionStatus, error) {
+	var sessionStatus vpn.L2VPNSessionStatus
+	if err := c.apiClient.get(ctx, managerBasePath+"/vpn/l2vpn/sessions/"+sessionID+"/status", nil, &sessionStatus); err != nil {
+		return vpn.L2VPNSessionStatus{}, err
+	}
+	return sessionStatus, nil
+}
+
+func (c *nsxtClient) GetL2VPNSessionStatistics(ctx context.Context, sessionID string) (vpn.L2VPNSessionStatistics, error) {
+	var sessionStatistics vpn.L2VPNSessionStatistics
+	if err := c.apiClient.get(ctx, managerBasePath+"/vpn/l2vpn/sessions/"+sessionID+"/statistics", nil, &sessionStatistics); err != nil {
+		return vpn.L2VPNSessionStatistics{}, err
+	}
+	return sessionStatistics, nil
 }
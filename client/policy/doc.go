@@ -0,0 +1,3 @@
+// Package policy holds the models of the NSX-T Policy API, which the
+// generated NSX-T client does not cover.
+package policy
@@ -0,0 +1,64 @@
+package policy
+
+import "github.com/vmware/go-vmware-nsxt/manager"
+
+// Tier0 is a Tier-0 gateway.
+type Tier0 struct {
+	// Identifier of the gateway
+	Id string `json:"id"`
+	// Display name of the gateway
+	DisplayName string `json:"display_name,omitempty"`
+	// Absolute path of the gateway
+	Path string `json:"path,omitempty"`
+	// High availability mode, ACTIVE_ACTIVE or ACTIVE_STANDBY
+	HaMode string `json:"ha_mode,omitempty"`
+	// Failover mode, PREEMPTIVE or NON_PREEMPTIVE
+	FailoverMode string `json:"failover_mode,omitempty"`
+}
+
+// Tier0ListResult is a page of Tier-0 gateways.
+type Tier0ListResult struct {
+	Results []Tier0 `json:"results"`
+	// Opaque cursor to be used for getting next page of records
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// Tier0GatewayState is the realized state of a Tier-0 gateway.
+type Tier0GatewayState struct {
+	// Realized state of the gateway on every edge
+	Tier0State manager.ConfigurationState `json:"tier0_state,omitempty"`
+	// High availability status of the gateway on every edge
+	Tier0Status manager.LogicalRouterStatus `json:"tier0_status,omitempty"`
+}
+
+// Tier1 is a Tier-1 gateway.
+type Tier1 struct {
+	// Identifier of the gateway
+	Id string `json:"id"`
+	// Display name of the gateway
+	DisplayName string `json:"display_name,omitempty"`
+	// Absolute path of the gateway
+	Path string `json:"path,omitempty"`
+	// Path of the Tier-0 gateway the gateway is connected to
+	Tier0Path string `json:"tier0_path,omitempty"`
+	// High availability mode, ACTIVE_STANDBY or unset for gateways without
+	// services on edges
+	HaMode string `json:"ha_mode,omitempty"`
+	// Failover mode, PREEMPTIVE or NON_PREEMPTIVE
+	FailoverMode string `json:"failover_mode,omitempty"`
+}
+
+// Tier1ListResult is a page of Tier-1 gateways.
+type Tier1ListResult struct {
+	Results []Tier1 `json:"results"`
+	// Opaque cursor to be used for getting next page of records
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// Tier1GatewayState is the realized state of a Tier-1 gateway.
+type Tier1GatewayState struct {
+	// Realized state of the gateway on every edge
+	Tier1State manager.ConfigurationState `json:"tier1_state,omitempty"`
+	// High availability status of the gateway on every edge
+	Tier1Status manager.LogicalRouterStatus `json:"tier1_status,omitempty"`
+}
@@ -0,0 +1,99 @@
+package policy
+
+// Domain is a domain holding security policies.
+type Domain struct {
+	// Identifier of the domain
+	Id string `json:"id"`
+	// Display name of the domain
+	DisplayName string `json:"display_name,omitempty"`
+	// Absolute path of the domain
+	Path string `json:"path,omitempty"`
+}
+
+// DomainListResult is a page of domains.
+type DomainListResult struct {
+	Results []Domain `json:"results"`
+	// Opaque cursor to be used for getting next page of records
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// SecurityPolicy is a distributed firewall security policy.
+type SecurityPolicy struct {
+	// Identifier of the security policy
+	Id string `json:"id"`
+	// Display name of the security policy
+	DisplayName string `json:"display_name,omitempty"`
+	// Absolute path of the security policy
+	Path string `json:"path,omitempty"`
+	// Category, like Emergency, Infrastructure, Environment or Application
+	Category string `json:"category,omitempty"`
+}
+
+// SecurityPolicyListResult is a page of security policies.
+type SecurityPolicyListResult struct {
+	Results []SecurityPolicy `json:"results"`
+	// Opaque cursor to be used for getting next page of records
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// Rule is a rule of a security policy.
+type Rule struct {
+	// Identifier of the rule
+	Id string `json:"id"`
+	// Display name of the rule
+	DisplayName string `json:"display_name,omitempty"`
+	// Absolute path of the rule
+	Path string `json:"path,omitempty"`
+	// Action, ALLOW, DROP, REJECT or JUMP_TO_APPLICATION
+	Action string `json:"action,omitempty"`
+	// Unique numeric identifier of the rule
+	RuleId int64 `json:"rule_id,omitempty"`
+}
+
+// RuleListResult is a page of rules.
+type RuleListResult struct {
+	Results []Rule `json:"results"`
+	// Opaque cursor to be used for getting next page of records
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// RuleStatistics are the statistics of a rule.
+type RuleStatistics struct {
+	// Path of the rule
+	Rule string `json:"rule,omitempty"`
+	// Numeric identifier of the rule
+	InternalRuleId string `json:"internal_rule_id,omitempty"`
+	// Aggregated number of hits received by the rule
+	HitCount int64 `json:"hit_count"`
+	// Aggregated number of packets processed by the rule
+	PacketCount int64 `json:"packet_count"`
+	// Aggregated number of bytes processed by the rule
+	ByteCount int64 `json:"byte_count"`
+	// Aggregated number of sessions processed by the rule
+	SessionCount int64 `json:"session_count"`
+	// Maximum value of popularity index of all rules of the type
+	MaxPopularityIndex int64 `json:"max_popularity_index"`
+	// Popularity index of the rule
+	PopularityIndex int64 `json:"popularity_index"`
+}
+
+// RuleStatisticsListResult are the statistics of the rules of a security
+// policy.
+type RuleStatisticsListResult struct {
+	Results []RuleStatistics `json:"results"`
+}
+
+// SecurityPolicyStatistics are the statistics of a security policy on an
+// enforcement point.
+type SecurityPolicyStatistics struct {
+	// Path of the enforcement point
+	EnforcementPointPath string `json:"enforcement_point_path,omitempty"`
+	// Statistics of the rules of the security policy
+	Statistics RuleStatisticsListResult `json:"statistics"`
+}
+
+// SecurityPolicyStatisticsListResult are the statistics of a security policy
+// on every enforcement point.
+type SecurityPolicyStatisticsListResult struct {
+	Results []SecurityPolicyStatistics `json:"results"`
+}
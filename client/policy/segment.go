@@ -0,0 +1,47 @@
+package policy
+
+import "github.com/vmware/go-vmware-nsxt/manager"
+
+// Segment is a layer 2 segment.
+type Segment struct {
+	// Identifier of the segment
+	Id string `json:"id"`
+	// Display name of the segment
+	DisplayName string `json:"display_name,omitempty"`
+	// Absolute path of the segment
+	Path string `json:"path,omitempty"`
+	// Path of the Tier-0 or Tier-1 gateway the segment is connected to
+	ConnectivityPath string `json:"connectivity_path,omitempty"`
+	// Path of the transport zone of the segment
+	TransportZonePath string `json:"transport_zone_path,omitempty"`
+	// Admin state, UP or DOWN
+	AdminState string `json:"admin_state,omitempty"`
+}
+
+// SegmentListResult is a page of segments.
+type SegmentListResult struct {
+	Results []Segment `json:"results"`
+	// Opaque cursor to be used for getting next page of records
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// SegmentPortListResult is a page of ports of a segment.
+type SegmentPortListResult struct {
+	// Count of ports in all pages
+	ResultCount int64 `json:"result_count,omitempty"`
+	// Opaque cursor to be used for getting next page of records
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// SegmentStatistics are the statistics of a segment on an enforcement point.
+type SegmentStatistics struct {
+	manager.LogicalSwitchStatistics
+	// Path of the enforcement point
+	EnforcementPointPath string `json:"enforcement_point_path,omitempty"`
+}
+
+// SegmentStatisticsListResult are the statistics of a segment on every
+// enforcement point.
+type SegmentStatisticsListResult struct {
+	Results []SegmentStatistics `json:"results"`
+}
@@ -0,0 +1,194 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"nsxt_exporter/client/policy"
+
+	"github.com/go-kit/kit/log"
+	"github.com/vmware/go-vmware-nsxt/manager"
+)
+
+const policyBasePath = "/policy/api/v1"
+
+type policyClient struct {
+	apiClient *APIClient
+	logger    log.Logger
+}
+
+// NewPolicyClient returns a client for the Policy API of the NSX-T manager of
+// apiClient.
+func NewPolicyClient(apiClient *APIClient, logger log.Logger) *policyClient {
+	return &policyClient{
+		apiClient: apiClient,
+		logger:    logger,
+	}
+}
+
+// get sends a GET request for path below the Policy API base path.
+func (c *policyClient) get(ctx context.Context, path string, query url.Values, v interface{}) error {
+	return c.apiClient.get(ctx, policyBasePath+path, query, v)
+}
+
+// cursorQuery returns the query to request the page of a list at cursor.
+func cursorQuery(cursor string) url.Values {
+	query := url.Values{}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	return query
+}
+
+func (c *policyClient) ListAllTier0s(ctx context.Context) ([]policy.Tier0, error) {
+	var tier0s []policy.Tier0
+	var cursor string
+	for {
+		var tier0sResult policy.Tier0ListResult
+		if err := c.get(ctx, "/infra/tier-0s", cursorQuery(cursor), &tier0sResult); err != nil {
+			return nil, err
+		}
+		tier0s = append(tier0s, tier0sResult.Results...)
+		cursor = tier0sResult.Cursor
+		if len(cursor) == 0 {
+			break
+		}
+	}
+	return tier0s, nil
+}
+
+func (c *policyClient) GetTier0State(ctx context.Context, tier0ID string) (policy.Tier0GatewayState, error) {
+	var tier0State policy.Tier0GatewayState
+	if err := c.get(ctx, "/infra/tier-0s/"+tier0ID+"/state", nil, &tier0State); err != nil {
+		return policy.Tier0GatewayState{}, err
+	}
+	return tier0State, nil
+}
+
+func (c *policyClient) ListAllTier1s(ctx context.Context) ([]policy.Tier1, error) {
+	var tier1s []policy.Tier1
+	var cursor string
+	for {
+		var tier1sResult policy.Tier1ListResult
+		if err := c.get(ctx, "/infra/tier-1s", cursorQuery(cursor), &tier1sResult); err != nil {
+			return nil, err
+		}
+		tier1s = append(tier1s, tier1sResult.Results...)
+		cursor = tier1sResult.Cursor
+		if len(cursor) == 0 {
+			break
+		}
+	}
+	return tier1s, nil
+}
+
+func (c *policyClient) GetTier1State(ctx context.Context, tier1ID string) (policy.Tier1GatewayState, error) {
+	var tier1State policy.Tier1GatewayState
+	if err := c.get(ctx, "/infra/tier-1s/"+tier1ID+"/state", nil, &tier1State); err != nil {
+		return policy.Tier1GatewayState{}, err
+	}
+	return tier1State, nil
+}
+
+func (c *policyClient) ListAllSegments(ctx context.Context) ([]policy.Segment, error) {
+	var segments []policy.Segment
+	var cursor string
+	for {
+		var segmentsResult policy.SegmentListResult
+		if err := c.get(ctx, "/infra/segments", cursorQuery(cursor), &segmentsResult); err != nil {
+			return nil, err
+		}
+		segments = append(segments, segmentsResult.Results...)
+		cursor = segmentsResult.Cursor
+		if len(cursor) == 0 {
+			break
+		}
+	}
+	return segments, nil
+}
+
+func (c *policyClient) GetSegmentState(ctx context.Context, segmentID string) (manager.ConfigurationState, error) {
+	var segmentState manager.ConfigurationState
+	if err := c.get(ctx, "/infra/segments/"+segmentID+"/state", nil, &segmentState); err != nil {
+		return manager.ConfigurationState{}, err
+	}
+	return segmentState, nil
+}
+
+// GetSegmentPortCount returns the number of ports of a segment. Only the
+// first port is requested as the count covers all pages.
+func (c *policyClient) GetSegmentPortCount(ctx context.Context, segmentID string) (int64, error) {
+	query := url.Values{}
+	query.Set("page_size", "1")
+	var portsResult policy.SegmentPortListResult
+	if err := c.get(ctx, "/infra/segments/"+segmentID+"/ports", query, &portsResult); err != nil {
+		return 0, err
+	}
+	return portsResult.ResultCount, nil
+}
+
+func (c *policyClient) GetSegmentStatistics(ctx context.Context, segmentID string) ([]policy.SegmentStatistics, error) {
+	var statisticsResult policy.SegmentStatisticsListResult
+	if err := c.get(ctx, "/infra/segments/"+segmentID+"/statistics", nil, &statisticsResult); err != nil {
+		return nil, err
+	}
+	return statisticsResult.Results, nil
+}
+
+func (c *policyClient) ListAllDomains(ctx context.Context) ([]policy.Domain, error) {
+	var domains []policy.Domain
+	var cursor string
+	for {
+		var domainsResult policy.DomainListResult
+		if err := c.get(ctx, "/infra/domains", cursorQuery(cursor), &domainsResult); err != nil {
+			return nil, err
+		}
+		domains = append(domains, domainsResult.Results...)
+		cursor = domainsResult.Cursor
+		if len(cursor) == 0 {
+			break
+		}
+	}
+	return domains, nil
+}
+
+func (c *policyClient) ListAllSecurityPolicies(ctx context.Context, domainID string) ([]policy.SecurityPolicy, error) {
+	var securityPolicies []policy.SecurityPolicy
+	var cursor string
+	for {
+		var securityPoliciesResult policy.SecurityPolicyListResult
+		if err := c.get(ctx, "/infra/domains/"+domainID+"/security-policies", cursorQuery(cursor), &securityPoliciesResult); err != nil {
+			return nil, err
+		}
+		securityPolicies = append(securityPolicies, securityPoliciesResult.Results...)
+		cursor = securityPoliciesResult.Cursor
+		if len(cursor) == 0 {
+			break
+		}
+	}
+	return securityPolicies, nil
+}
+
+func (c *policyClient) ListAllRules(ctx context.Context, domainID, securityPolicyID string) ([]policy.Rule, error) {
+	var rules []policy.Rule
+	var cursor string
+	for {
+		var rulesResult policy.RuleListResult
+		if err := c.get(ctx, "/infra/domains/"+domainID+"/security-policies/"+securityPolicyID+"/rules", cursorQuery(cursor), &rulesResult); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rulesResult.Results...)
+		cursor = rulesResult.Cursor
+		if len(cursor) == 0 {
+			break
+		}
+	}
+	return rules, nil
+}
+
+func (c *policyClient) GetSecurityPolicyStatistics(ctx context.Context, domainID, securityPolicyID string) ([]policy.SecurityPolicyStatistics, error) {
+	var statisticsResult policy.SecurityPolicyStatisticsListResult
+	if err := c.get(ctx, "/infra/domains/"+domainID+"/security-policies/"+securityPolicyID+"/statistics", nil, &statisticsResult); err != nil {
+		return nil, err
+	}
+	return statisticsResult.Results, nil
+}
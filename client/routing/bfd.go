@@ -0,0 +1,32 @@
+package routing
+
+// BfdPeerStatus is the status of a BFD session of a logical router with a
+// peer on a transport node.
+type BfdPeerStatus struct {
+	// IP address of the BFD peer
+	PeerIpAddress string `json:"peer_ip_address,omitempty"`
+	// IP address of the logical router port the session is established from
+	SourceIpAddress string `json:"source_ip_address,omitempty"`
+	// State of the BFD session, UP, DOWN, INIT or ADMIN_DOWN
+	State string `json:"state,omitempty"`
+	// A short message indicating what the BFD session thinks is wrong in case of a problem
+	Diagnostic string `json:"diagnostic,omitempty"`
+	// State of the remote interface's BFD session
+	RemoteState string `json:"remote_state,omitempty"`
+	// A short message indicating what the remote interface's BFD session thinks is wrong in case of a problem
+	RemoteDiagnostic string `json:"remote_diagnostic,omitempty"`
+	// Timestamp in milliseconds of the last change of the session state
+	LastStateChangeTime int64 `json:"last_state_change_time,omitempty"`
+}
+
+// BfdPeerStatusListResult is a page of BFD sessions of a logical router on a
+// transport node.
+type BfdPeerStatusListResult struct {
+	// Logical router id
+	LogicalRouterId string `json:"logical_router_id,omitempty"`
+	// Transport node id
+	TransportNodeId string          `json:"transport_node_id,omitempty"`
+	Results         []BfdPeerStatus `json:"results"`
+	// Opaque cursor to be used for getting next page of records
+	Cursor string `json:"cursor,omitempty"`
+}
@@ -0,0 +1,3 @@
+// Package routing holds the models of the NSX-T Manager API for logical
+// router routing, which the generated NSX-T client does not cover.
+package routing
@@ -0,0 +1,254 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+)
+
+const (
+	sessionCreatePath  = "/api/session/create"
+	sessionDestroyPath = "/api/session/destroy"
+	xsrfTokenHeader    = "X-XSRF-TOKEN"
+	// maxErrorBodySize is how much of the body of a 403 response is read to
+	// tell a rejected session from a missing permission.
+	maxErrorBodySize = 64 * 1024
+)
+
+// errSessionClosed is returned for requests sent after Logout.
+var errSessionClosed = errors.New("session is logged out")
+
+// sessionErrorMarkers are the lower case parts of the messages of 403
+// responses to requests with an expired session or an invalid XSRF token.
+var sessionErrorMarkers = []string{"xsrf", "session", "credentials were incorrect"}
+
+// session is the authenticated session of a user on a NSX-T manager.
+type session struct {
+	url    url.URL
+	cookie string
+	xsrf   string
+}
+
+// Credentials returns the username and password to log in with.
+type Credentials func() (username, password string, err error)
+
+// Session authenticates requests with a NSX-T session instead of sending the
+// credentials with every request. The session is created on the first request
+// and created again when the manager rejects it, e.g. after it expired. The
+// credentials are read again for every new session. Once logged out, a
+// Session doesn't log in again.
+type Session struct {
+	next        http.RoundTripper
+	credentials Credentials
+	logger      log.Logger
+
+	mtx     sync.Mutex
+	current *session
+	closed  bool
+}
+
+// NewSession returns a Session logging in with credentials and sending
+// requests through next.
+func NewSession(next http.RoundTripper, credentials Credentials, logger log.Logger) *Session {
+	return &Session{
+		next:        next,
+		credentials: credentials,
+		logger:      logger,
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface. Requests to create a
+// session, sent by the NSX-T client when it is created, log in with the
+// credentials of the Session and return its response.
+func (s *Session) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Path == sessionCreatePath {
+		s.mtx.Lock()
+		old := s.current
+		_, resp, err := s.login(req.Context(), *req.URL)
+		s.mtx.Unlock()
+		if old != nil {
+			s.discard(req.Context(), old)
+		}
+		if resp != nil {
+			return resp, nil
+		}
+		return nil, err
+	}
+	current, err := s.session(req.Context(), *req.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.next.RoundTrip(authenticate(req, current))
+	if err != nil || !rejectsSession(resp) {
+		return resp, err
+	}
+	retryReq, ok := rewindRequest(req)
+	if !ok {
+		return resp, nil
+	}
+	current, err = s.session(req.Context(), *req.URL, current)
+	if err != nil {
+		level.Warn(s.logger).Log("msg", "Unable to renew session", "err", err)
+		return resp, nil
+	}
+	resp.Body.Close()
+	return s.next.RoundTrip(authenticate(retryReq, current))
+}
+
+// rejectsSession returns whether the manager rejected the session of the
+// request of resp, either with 401 or with a 403 about the session or its
+// XSRF token. Other 403 responses deny the user permission and are returned
+// as is. The body of resp is restored after reading it.
+func rejectsSession(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return true
+	case http.StatusForbidden:
+	default:
+		return false
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	if err != nil {
+		return false
+	}
+	message := strings.ToLower(string(body))
+	for _, marker := range sessionErrorMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Logout destroys the current session, if any. Requests sent afterwards fail
+// instead of logging in again.
+func (s *Session) Logout(ctx context.Context) error {
+	s.mtx.Lock()
+	current := s.current
+	s.current = nil
+	s.closed = true
+	s.mtx.Unlock()
+	if current == nil {
+		return nil
+	}
+	return s.destroy(ctx, current)
+}
+
+// destroy destroys the given session on the manager.
+func (s *Session) destroy(ctx context.Context, old *session) error {
+	u := old.url
+	u.Path = sessionDestroyPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.next.RoundTrip(authenticate(req, old))
+	if err != nil {
+		return fmt.Errorf("unable to destroy session: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to destroy session: %s", resp.Status)
+	}
+	return nil
+}
+
+// session returns the current session unless it is stale, logging in if
+// there's no usable session. Only the first of concurrent requests rejected
+// with the same session logs in again, the others use its new session.
+func (s *Session) session(ctx context.Context, u url.URL, stale *session) (*session, error) {
+	s.mtx.Lock()
+	if s.current != nil && s.current != stale {
+		defer s.mtx.Unlock()
+		return s.current, nil
+	}
+	old := s.current
+	current, resp, err := s.login(ctx, u)
+	s.mtx.Unlock()
+	if resp != nil {
+		resp.Body.Close()
+	}
+	if old != nil {
+		s.discard(ctx, old)
+	}
+	return current, err
+}
+
+// discard destroys a session replaced by a new one. The manager may have
+// already expired it, so errors are only logged at debug level.
+func (s *Session) discard(ctx context.Context, old *session) {
+	if err := s.destroy(ctx, old); err != nil {
+		level.Debug(s.logger).Log("msg", "Unable to destroy replaced session", "err", err)
+	}
+}
+
+// login creates a new session on the manager given by u, replacing the
+// current one. The response is also returned if the manager rejected the
+// login. s.mtx must be held.
+func (s *Session) login(ctx context.Context, u url.URL) (*session, *http.Response, error) {
+	if s.closed {
+		return nil, nil, errSessionClosed
+	}
+	s.current = nil
+	u.Path = sessionCreatePath
+	u.RawQuery = ""
+	username, password, err := s.credentials()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read credentials: %s", err)
+	}
+	form := url.Values{}
+	form.Set("j_username", username)
+	form.Set("j_password", password)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.next.RoundTrip(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create session: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp, fmt.Errorf("unable to create session: %s", resp.Status)
+	}
+	var cookies []string
+	for _, cookie := range resp.Cookies() {
+		cookies = append(cookies, cookie.Name+"="+cookie.Value)
+	}
+	s.current = &session{
+		url:    u,
+		cookie: strings.Join(cookies, "; "),
+		xsrf:   resp.Header.Get(xsrfTokenHeader),
+	}
+	level.Debug(s.logger).Log("msg", "Created session", "host", u.Host, "username", username)
+	return s.current, resp, nil
+}
+
+// authenticate returns a copy of req authenticated by the session instead of
+// the credentials or session headers set by the NSX-T client.
+func authenticate(req *http.Request, current *session) *http.Request {
+	req = req.Clone(req.Context())
+	req.Header.Del("Authorization")
+	req.Header.Set("Cookie", current.cookie)
+	if current.xsrf != "" {
+		req.Header.Set(xsrfTokenHeader, current.xsrf)
+	} else {
+		req.Header.Del(xsrfTokenHeader)
+	}
+	return req
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSessionManager serves the session endpoints of a NSX-T manager and
+// answers other requests with the status of rejectStatus and body of
+// rejectBody for sessions in rejected.
+type fakeSessionManager struct {
+	rejectStatus int
+	rejectBody   string
+
+	mtx       sync.Mutex
+	logins    int
+	destroyed []string
+	rejected  map[string]bool
+}
+
+func (m *fakeSessionManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	cookie, _ := r.Cookie("JSESSIONID")
+	switch r.URL.Path {
+	case sessionCreatePath:
+		m.logins++
+		http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: strconv.Itoa(m.logins)})
+	case sessionDestroyPath:
+		m.destroyed = append(m.destroyed, cookie.Value)
+	default:
+		if cookie == nil || m.rejected[cookie.Value] {
+			w.WriteHeader(m.rejectStatus)
+			w.Write([]byte(m.rejectBody))
+		}
+	}
+}
+
+func TestSession_RoundTrip(t *testing.T) {
+	testcases := []struct {
+		description       string
+		rejectStatus      int
+		rejectBody        string
+		expectedStatus    int
+		expectedLogins    int
+		expectedDestroyed []string
+	}{
+		{
+			description:       "Should renew session rejected with 401",
+			rejectStatus:      http.StatusUnauthorized,
+			expectedStatus:    http.StatusOK,
+			expectedLogins:    2,
+			expectedDestroyed: []string{"1"},
+		},
+		{
+			description:       "Should renew session rejected with 403 for invalid XSRF token",
+			rejectStatus:      http.StatusForbidden,
+			rejectBody:        `{"error_code": 403, "error_message": "Bad XSRF token"}`,
+			expectedStatus:    http.StatusOK,
+			expectedLogins:    2,
+			expectedDestroyed: []string{"1"},
+		},
+		{
+			description:    "Should not renew session when user lacks permission",
+			rejectStatus:   http.StatusForbidden,
+			rejectBody:     `{"error_code": 403, "error_message": "The user does not have permission to perform this operation."}`,
+			expectedStatus: http.StatusForbidden,
+			expectedLogins: 1,
+		},
+	}
+	for _, tc := range testcases {
+		manager := &fakeSessionManager{
+			rejectStatus: tc.rejectStatus,
+			rejectBody:   tc.rejectBody,
+			rejected:     map[string]bool{"1": true},
+		}
+		server := httptest.NewServer(manager)
+		s := NewSession(http.DefaultTransport, func() (string, string, error) { return "admin", "secret", nil }, log.NewNopLogger())
+
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/fabric/nodes", nil)
+		resp, err := s.RoundTrip(req)
+		if assert.NoError(t, err, tc.description) {
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode, tc.description)
+			resp.Body.Close()
+		}
+		assert.Equal(t, tc.expectedLogins, manager.logins, tc.description)
+		assert.Equal(t, tc.expectedDestroyed, manager.destroyed, tc.description)
+		server.Close()
+	}
+}
+
+func TestSession_RoundTripConcurrent(t *testing.T) {
+	manager := &fakeSessionManager{
+		rejectStatus: http.StatusUnauthorized,
+		rejected:     map[string]bool{"1": true},
+	}
+	server := httptest.NewServer(manager)
+	defer server.Close()
+	s := NewSession(http.DefaultTransport, func() (string, string, error) { return "admin", "secret", nil }, log.NewNopLogger())
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/fabric/nodes", nil)
+	s.session(context.Background(), *req.URL, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := s.RoundTrip(req)
+			if assert.NoError(t, err) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 2, manager.logins, "Should log in once for concurrent requests rejected with the same session")
+	assert.Equal(t, []string{"1"}, manager.destroyed, "Should destroy rejected session once")
+}
+
+func TestSession_Logout(t *testing.T) {
+	manager := &fakeSessionManager{rejected: map[string]bool{}}
+	server := httptest.NewServer(manager)
+	defer server.Close()
+	s := NewSession(http.DefaultTransport, func() (string, string, error) { return "admin", "secret", nil }, log.NewNopLogger())
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/fabric/nodes", nil)
+	resp, err := s.RoundTrip(req)
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+	}
+	assert.NoError(t, s.Logout(context.Background()))
+	assert.Equal(t, []string{"1"}, manager.destroyed, "Should destroy session on logout")
+
+	_, err = s.RoundTrip(req)
+	assert.Equal(t, errSessionClosed, err, "Should not log in again after logout")
+	assert.Equal(t, 1, manager.logins, "Should not log in again after logout")
+}
@@ -0,0 +1,176 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+// maxRetryBackoff is the longest time to wait before retrying a request. A
+// request is not retried if the manager asks to wait longer.
+const maxRetryBackoff = 30 * time.Second
+
+var (
+	apiThrottled = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "nsxt",
+		Subsystem: "api",
+		Name:      "throttled_total",
+		Help:      "Number of NSX-T API requests rejected by the manager with 429 or 503.",
+	})
+	apiRetries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "nsxt",
+		Subsystem: "api",
+		Name:      "retries_total",
+		Help:      "Number of retried NSX-T API requests.",
+	})
+
+	limitersMtx sync.Mutex
+	limiters    = make(map[string]*rate.Limiter)
+)
+
+func init() {
+	prometheus.MustRegister(apiThrottled)
+	prometheus.MustRegister(apiRetries)
+}
+
+// ThrottleOpts configures how requests to a NSX-T manager are rate limited
+// and retried.
+type ThrottleOpts struct {
+	// RateLimit is the number of requests per second. No limit if 0.
+	RateLimit float64
+	// RateBurst is the number of requests that may be sent at once.
+	RateBurst int
+	// MaxRetries is the number of times a throttled request is retried.
+	MaxRetries int
+	// RetryBackoff is the time to wait before the first retry. It doubles on
+	// every further retry.
+	RetryBackoff time.Duration
+}
+
+// throttledTransport limits the rate of requests with a token bucket and
+// retries requests the manager rejected with 429 or 503.
+type throttledTransport struct {
+	next    http.RoundTripper
+	limiter *rate.Limiter
+	opts    ThrottleOpts
+	logger  log.Logger
+}
+
+// NewThrottledTransport returns a RoundTripper sending requests through next.
+// Transports with the same key share a limiter, as managers limit the rate of
+// requests per user.
+func NewThrottledTransport(next http.RoundTripper, key string, opts ThrottleOpts, logger log.Logger) http.RoundTripper {
+	t := &throttledTransport{
+		next:   next,
+		opts:   opts,
+		logger: logger,
+	}
+	if opts.RateLimit > 0 {
+		t.limiter = sharedLimiter(key, opts)
+	}
+	return t
+}
+
+func sharedLimiter(key string, opts ThrottleOpts) *rate.Limiter {
+	limitersMtx.Lock()
+	defer limitersMtx.Unlock()
+	limiter, ok := limiters[key]
+	if !ok {
+		burst := opts.RateBurst
+		if burst < 1 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(opts.RateLimit), burst)
+		limiters[key] = limiter
+	}
+	return limiter
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *throttledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	backoff := t.opts.RetryBackoff
+	for retry := 0; ; retry++ {
+		if t.limiter != nil {
+			if err := t.limiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+			return resp, err
+		}
+		apiThrottled.Inc()
+		if retry >= t.opts.MaxRetries {
+			return resp, nil
+		}
+		wait := backoff
+		if after, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			wait = after
+		}
+		if wait > maxRetryBackoff {
+			return resp, nil
+		}
+		retryReq, ok := rewindRequest(req)
+		if !ok {
+			return resp, nil
+		}
+		resp.Body.Close()
+		level.Debug(t.logger).Log("msg", "Retrying throttled request", "url", req.URL, "status", resp.StatusCode, "wait", wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+		apiRetries.Inc()
+		req = retryReq
+		backoff *= 2
+	}
+}
+
+// retryAfter parses the Retry-After header, given either in seconds or as
+// HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(v); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// rewindRequest returns a copy of req to send again. Requests with a body
+// that can't be read again are not retried.
+func rewindRequest(req *http.Request) (*http.Request, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	retryReq := req.Clone(req.Context())
+	retryReq.Body = body
+	return retryReq, true
+}
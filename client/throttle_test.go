@@ -0,0 +1,171 @@
+package client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryAfter(t *testing.T) {
+	testcases := []struct {
+		description  string
+		value        string
+		expectedWait time.Duration
+		expectedOk   bool
+	}{
+		{
+			description:  "Should parse seconds",
+			value:        "3",
+			expectedWait: 3 * time.Second,
+			expectedOk:   true,
+		},
+		{
+			description:  "Should parse HTTP date in the future",
+			value:        time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat),
+			expectedWait: 10 * time.Second,
+			expectedOk:   true,
+		},
+		{
+			description:  "Should not wait for HTTP date in the past",
+			value:        time.Now().Add(-10 * time.Second).UTC().Format(http.TimeFormat),
+			expectedWait: 0,
+			expectedOk:   true,
+		},
+		{
+			description: "Should ignore negative seconds",
+			value:       "-1",
+			expectedOk:  false,
+		},
+		{
+			description: "Should ignore invalid value",
+			value:       "soon",
+			expectedOk:  false,
+		},
+		{
+			description: "Should ignore missing header",
+			value:       "",
+			expectedOk:  false,
+		},
+	}
+	for _, tc := range testcases {
+		wait, ok := retryAfter(tc.value)
+		assert.Equal(t, tc.expectedOk, ok, tc.description)
+		assert.InDelta(t, float64(tc.expectedWait), float64(wait), float64(time.Second), tc.description)
+	}
+}
+
+func TestRewindRequest(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://nsx.example.com/api/v1/fabric/nodes", strings.NewReader("payload"))
+	ioutil.ReadAll(req.Body)
+	retryReq, ok := rewindRequest(req)
+	if assert.True(t, ok, "Should rewind request with GetBody") {
+		body, _ := ioutil.ReadAll(retryReq.Body)
+		assert.Equal(t, "payload", string(body), "Should send body of request again")
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "https://nsx.example.com/api/v1/fabric/nodes", nil)
+	retryReq, ok = rewindRequest(req)
+	assert.True(t, ok, "Should rewind request without body")
+	assert.Equal(t, req, retryReq, "Should send request without body as is")
+
+	req, _ = http.NewRequest(http.MethodPost, "https://nsx.example.com/api/v1/fabric/nodes", ioutil.NopCloser(strings.NewReader("payload")))
+	_, ok = rewindRequest(req)
+	assert.False(t, ok, "Should not rewind request with body that can't be read again")
+}
+
+func TestThrottledTransport_RoundTrip(t *testing.T) {
+	testcases := []struct {
+		description       string
+		statuses          []int
+		retryAfter        string
+		maxRetries        int
+		expectedStatus    int
+		expectedRequests  int
+		expectedThrottled float64
+		expectedRetries   float64
+	}{
+		{
+			description:      "Should not retry successful request",
+			statuses:         []int{http.StatusOK},
+			maxRetries:       3,
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 1,
+		},
+		{
+			description:       "Should retry request rejected with 429 and 503",
+			statuses:          []int{http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusOK},
+			maxRetries:        3,
+			expectedStatus:    http.StatusOK,
+			expectedRequests:  3,
+			expectedThrottled: 2,
+			expectedRetries:   2,
+		},
+		{
+			description:       "Should return rejected response after max retries",
+			statuses:          []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests},
+			maxRetries:        2,
+			expectedStatus:    http.StatusTooManyRequests,
+			expectedRequests:  3,
+			expectedThrottled: 3,
+			expectedRetries:   2,
+		},
+		{
+			description:       "Should not retry when Retry-After exceeds max backoff",
+			statuses:          []int{http.StatusServiceUnavailable, http.StatusOK},
+			retryAfter:        "60",
+			maxRetries:        3,
+			expectedStatus:    http.StatusServiceUnavailable,
+			expectedRequests:  1,
+			expectedThrottled: 1,
+		},
+		{
+			description:      "Should not retry other errors",
+			statuses:         []int{http.StatusInternalServerError, http.StatusOK},
+			maxRetries:       3,
+			expectedStatus:   http.StatusInternalServerError,
+			expectedRequests: 1,
+		},
+	}
+	for _, tc := range testcases {
+		var mtx sync.Mutex
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			body, _ := ioutil.ReadAll(r.Body)
+			status := tc.statuses[len(bodies)]
+			bodies = append(bodies, string(body))
+			if tc.retryAfter != "" {
+				w.Header().Set("Retry-After", tc.retryAfter)
+			}
+			w.WriteHeader(status)
+		}))
+		throttled, retries := testutil.ToFloat64(apiThrottled), testutil.ToFloat64(apiRetries)
+		transport := NewThrottledTransport(http.DefaultTransport, server.URL, ThrottleOpts{
+			MaxRetries:   tc.maxRetries,
+			RetryBackoff: time.Millisecond,
+		}, log.NewNopLogger())
+
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/v1/fabric/nodes", strings.NewReader("payload"))
+		resp, err := transport.RoundTrip(req)
+		if assert.NoError(t, err, tc.description) {
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode, tc.description)
+			resp.Body.Close()
+		}
+		assert.Len(t, bodies, tc.expectedRequests, tc.description)
+		for _, body := range bodies {
+			assert.Equal(t, "payload", body, tc.description)
+		}
+		assert.Equal(t, tc.expectedThrottled, testutil.ToFloat64(apiThrottled)-throttled, tc.description)
+		assert.Equal(t, tc.expectedRetries, testutil.ToFloat64(apiRetries)-retries, tc.description)
+		server.Close()
+	}
+}
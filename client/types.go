@@ -1,6 +1,11 @@
 package client
 
 import (
+	"context"
+	"nsxt_exporter/client/policy"
+	"nsxt_exporter/client/routing"
+	"nsxt_exporter/client/vpn"
+
 	"github.com/vmware/go-vmware-nsxt/administration"
 	"github.com/vmware/go-vmware-nsxt/loadbalancer"
 	"github.com/vmware/go-vmware-nsxt/manager"
@@ -8,73 +13,118 @@ import (
 
 // LogicalPortClient represents API group logical port for NSX-T client.
 type LogicalPortClient interface {
-	ListLogicalPorts(localVarOptionals map[string]interface{}) (manager.LogicalPortListResult, error)
-	GetLogicalPortOperationalStatus(lportID string, localVarOptionals map[string]interface{}) (manager.LogicalPortOperationalStatus, error)
+	ListLogicalPorts(ctx context.Context, localVarOptionals map[string]interface{}) (manager.LogicalPortListResult, error)
+	GetLogicalPortOperationalStatus(ctx context.Context, lportID string, localVarOptionals map[string]interface{}) (manager.LogicalPortOperationalStatus, error)
 }
 
 // LogicalRouterClient represents API group logical router for NSX-T client.
 type LogicalRouterClient interface {
-	ListAllLogicalRouters() ([]manager.LogicalRouter, error)
-	GetLogicalRouterStatus(logicalRouterID string) (manager.LogicalRouterStatus, error)
-	ListAllNatRules(logicalRouterID string) ([]manager.NatRule, error)
-	GetNatStatisticsPerRule(logicalRouterID, ruleID string) (manager.NatStatisticsPerRule, error)
+	ListAllLogicalRouters(ctx context.Context) ([]manager.LogicalRouter, error)
+	GetLogicalRouterStatus(ctx context.Context, logicalRouterID string) (manager.LogicalRouterStatus, error)
+	ListAllNatRules(ctx context.Context, logicalRouterID string) ([]manager.NatRule, error)
+	GetNatStatisticsPerRule(ctx context.Context, logicalRouterID, ruleID string) (manager.NatStatisticsPerRule, error)
+	ListAllBgpNeighborsStatus(ctx context.Context, logicalRouterID string) ([]manager.BgpNeighborStatus, error)
+	ListAllBfdPeersStatus(ctx context.Context, logicalRouterID, transportNodeID string) ([]routing.BfdPeerStatus, error)
+	ListAllRoutingTableRoutes(ctx context.Context, logicalRouterID, transportNodeID string) ([]manager.LogicalRouterRouteEntry, error)
+	ListAllForwardingTableRoutes(ctx context.Context, logicalRouterID, transportNodeID string) ([]manager.LogicalRouterRouteEntry, error)
 }
 
 // LogicalRouterPortClient represents API group logical router port for NSX-T client.
 type LogicalRouterPortClient interface {
-	ListAllLogicalRouterPorts() ([]manager.LogicalRouterPort, error)
-	GetLogicalRouterPortStatisticsSummary(lrportID string) (manager.LogicalRouterPortStatisticsSummary, error)
+	ListAllLogicalRouterPorts(ctx context.Context) ([]manager.LogicalRouterPort, error)
+	GetLogicalRouterPortStatisticsSummary(ctx context.Context, lrportID string) (manager.LogicalRouterPortStatisticsSummary, error)
 }
 
 // DHCPClient represents API group DHCP for NSX-T client.
 type DHCPClient interface {
-	ListAllDHCPServers() ([]manager.LogicalDhcpServer, error)
-	GetDhcpStatus(dhcpID string, localVarOptionals map[string]interface{}) (manager.DhcpServerStatus, error)
-	GetDHCPStatistic(dhcpID string) (manager.DhcpStatistics, error)
+	ListAllDHCPServers(ctx context.Context) ([]manager.LogicalDhcpServer, error)
+	GetDhcpStatus(ctx context.Context, dhcpID string, localVarOptionals map[string]interface{}) (manager.DhcpServerStatus, error)
+	GetDHCPStatistic(ctx context.Context, dhcpID string) (manager.DhcpStatistics, error)
 }
 
 // TransportNodeClient represents API group Transport Node for NSX-T client.
 type TransportNodeClient interface {
-	ListAllTransportNodes() ([]manager.TransportNode, error)
-	GetTransportNodeStatus(nodeID string) (manager.TransportNodeStatus, error)
-	ListAllEdgeClusters() ([]manager.EdgeCluster, error)
+	ListAllTransportNodes(ctx context.Context) ([]manager.TransportNode, error)
+	GetTransportNodeStatus(ctx context.Context, nodeID string) (manager.TransportNodeStatus, error)
+	ListAllEdgeClusters(ctx context.Context) ([]manager.EdgeCluster, error)
 }
 
 // SystemClient represents API group system for NSX-t client.
 type SystemClient interface {
-	ReadClusterStatus() (administration.ClusterStatus, error)
-	ReadClusterNodesAggregateStatus() (administration.ClustersAggregateInfo, error)
-	ReadApplianceManagementServiceStatus() (administration.NodeServiceStatusProperties, error)
-	ReadNSXMessageBusServiceStatus() (administration.NodeServiceStatusProperties, error)
-	ReadNTPServiceStatus() (administration.NodeServiceStatusProperties, error)
-	ReadNsxUpgradeAgentServiceStatus() (administration.NodeServiceStatusProperties, error)
-	ReadProtonServiceStatus() (administration.NodeServiceStatusProperties, error)
-	ReadProxyServiceStatus() (administration.NodeServiceStatusProperties, error)
-	ReadRabbitMQServiceStatus() (administration.NodeServiceStatusProperties, error)
-	ReadRepositoryServiceStatus() (administration.NodeServiceStatusProperties, error)
-	ReadSNMPServiceStatus() (administration.NodeServiceStatusProperties, error)
-	ReadSSHServiceStatus() (administration.NodeServiceStatusProperties, error)
-	ReadSearchServiceStatus() (administration.NodeServiceStatusProperties, error)
-	ReadSyslogServiceStatus() (administration.NodeServiceStatusProperties, error)
+	ReadClusterStatus(ctx context.Context) (administration.ClusterStatus, error)
+	ReadClusterNodesAggregateStatus(ctx context.Context) (administration.ClustersAggregateInfo, error)
+	ReadApplianceManagementServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error)
+	ReadNSXMessageBusServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error)
+	ReadNTPServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error)
+	ReadNsxUpgradeAgentServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error)
+	ReadProtonServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error)
+	ReadProxyServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error)
+	ReadRabbitMQServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error)
+	ReadRepositoryServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error)
+	ReadSNMPServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error)
+	ReadSSHServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error)
+	ReadSearchServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error)
+	ReadSyslogServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error)
 }
 
 // LogicalSwitchClient represents API group Logical Switch for NSX-T client.
 type LogicalSwitchClient interface {
-	ListAllLogicalSwitches() ([]manager.LogicalSwitch, error)
-	GetLogicalSwitchState(lswitchID string) (manager.LogicalSwitchState, error)
-	GetLogicalSwitchStatistic(lswitchID string) (manager.LogicalSwitchStatistics, error)
+	ListAllLogicalSwitches(ctx context.Context) ([]manager.LogicalSwitch, error)
+	GetLogicalSwitchState(ctx context.Context, lswitchID string) (manager.LogicalSwitchState, error)
+	GetLogicalSwitchStatistic(ctx context.Context, lswitchID string) (manager.LogicalSwitchStatistics, error)
 }
 
 // LoadBalancerClient represents API group Load Balancer for NSXT-T Client
 type LoadBalancerClient interface {
-	ListAllLoadBalancers() ([]loadbalancer.LbService, error)
-	GetLoadBalancerStatus(loadBalancerID string) (loadbalancer.LbServiceStatus, error)
-	GetLoadBalancerStatistic(loadBalancerID string) (loadbalancer.LbServiceStatistics, error)
+	ListAllLoadBalancers(ctx context.Context) ([]loadbalancer.LbService, error)
+	GetLoadBalancerStatus(ctx context.Context, loadBalancerID string) (loadbalancer.LbServiceStatus, error)
+	GetLoadBalancerStatistic(ctx context.Context, loadBalancerID string) (loadbalancer.LbServiceStatistics, error)
 }
 
 // FirewallClient represents Firewall sub-API group of Services for NSXT-T Client
 type FirewallClient interface {
-	ListAllFirewallSections() ([]manager.FirewallSection, error)
-	GetAllFirewallRules(sectionId string) ([]manager.FirewallRule, error)
-	GetFirewallStats(sectionId string, ruleId string) (manager.FirewallStats, error)
+	ListAllFirewallSections(ctx context.Context) ([]manager.FirewallSection, error)
+	GetAllFirewallRules(ctx context.Context, sectionId string) ([]manager.FirewallRule, error)
+	GetFirewallStats(ctx context.Context, sectionId string, ruleId string) (manager.FirewallStats, error)
+}
+
+// IPSecVPNClient represents API group IPSec VPN for NSX-T client.
+type IPSecVPNClient interface {
+	ListAllIPSecVPNSessions(ctx context.Context) ([]vpn.IPSecVPNSession, error)
+	ListAllIPSecVPNPeerEndpoints(ctx context.Context) ([]vpn.IPSecVPNPeerEndpoint, error)
+	GetIPSecVPNSessionStatus(ctx context.Context, sessionID string) (vpn.IPSecVPNSessionStatus, error)
+	GetIPSecVPNSessionStatistics(ctx context.Context, sessionID string) (vpn.IPSecVPNSessionStatistics, error)
+}
+
+// L2VPNClient represents API group L2VPN for NSX-T client. It lists the logical
+// switches to name the logical switches stretched by L2VPN sessions.
+type L2VPNClient interface {
+	ListAllL2VPNSessions(ctx context.Context) ([]vpn.L2VPNSession, error)
+	GetL2VPNSessionStatus(ctx context.Context, sessionID string) (vpn.L2VPNSessionStatus, error)
+	GetL2VPNSessionStatistics(ctx context.Context, sessionID string) (vpn.L2VPNSessionStatistics, error)
+	ListAllLogicalSwitches(ctx context.Context) ([]manager.LogicalSwitch, error)
+}
+
+// PolicyGatewayClient represents API group Tier-0 and Tier-1 gateways of the Policy API for NSX-T client.
+type PolicyGatewayClient interface {
+	ListAllTier0s(ctx context.Context) ([]policy.Tier0, error)
+	GetTier0State(ctx context.Context, tier0ID string) (policy.Tier0GatewayState, error)
+	ListAllTier1s(ctx context.Context) ([]policy.Tier1, error)
+	GetTier1State(ctx context.Context, tier1ID string) (policy.Tier1GatewayState, error)
+}
+
+// PolicySegmentClient represents API group segments of the Policy API for NSX-T client.
+type PolicySegmentClient interface {
+	ListAllSegments(ctx context.Context) ([]policy.Segment, error)
+	GetSegmentState(ctx context.Context, segmentID string) (manager.ConfigurationState, error)
+	GetSegmentPortCount(ctx context.Context, segmentID string) (int64, error)
+	GetSegmentStatistics(ctx context.Context, segmentID string) ([]policy.SegmentStatistics, error)
+}
+
+// PolicyFirewallClient represents API group distributed firewall security policies of the Policy API for NSX-T client.
+type PolicyFirewallClient interface {
+	ListAllDomains(ctx context.Context) ([]policy.Domain, error)
+	ListAllSecurityPolicies(ctx context.Context, domainID string) ([]policy.SecurityPolicy, error)
+	ListAllRules(ctx context.Context, domainID, securityPolicyID string) ([]policy.Rule, error)
+	GetSecurityPolicyStatistics(ctx context.Context, domainID, securityPolicyID string) ([]policy.SecurityPolicyStatistics, error)
 }
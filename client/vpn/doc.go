@@ -0,0 +1,3 @@
+// Package vpn holds the models of the NSX-T Manager API for IPSec and L2 VPN
+// services, which the generated NSX-T client does not cover.
+package vpn
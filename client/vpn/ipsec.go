@@ -0,0 +1,138 @@
+package vpn
+
+// IPSecVPNSession is an IPSec VPN session between a local and a peer
+// endpoint.
+type IPSecVPNSession struct {
+	// Unique identifier of this resource
+	Id string `json:"id,omitempty"`
+	// Defaults to ID if not set
+	DisplayName string `json:"display_name,omitempty"`
+	// PolicyBasedIPSecVPNSession or RouteBasedIPSecVPNSession
+	ResourceType string `json:"resource_type,omitempty"`
+	// Enable/Disable IPSec VPN session
+	Enabled bool `json:"enabled"`
+	// Identifier of VPN Service linked with local endpoint
+	IpsecVpnServiceId string `json:"ipsec_vpn_service_id,omitempty"`
+	// Local endpoint identifier
+	LocalEndpointId string `json:"local_endpoint_id,omitempty"`
+	// Peer endpoint identifier
+	PeerEndpointId string `json:"peer_endpoint_id,omitempty"`
+}
+
+// IPSecVPNSessionListResult is a page of IPSec VPN sessions.
+type IPSecVPNSessionListResult struct {
+	Results []IPSecVPNSession `json:"results"`
+	// Opaque cursor to be used for getting next page of records
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// IPSecVPNPeerEndpoint is the remote end of IPSec VPN sessions.
+type IPSecVPNPeerEndpoint struct {
+	// Unique identifier of this resource
+	Id string `json:"id,omitempty"`
+	// Defaults to ID if not set
+	DisplayName string `json:"display_name,omitempty"`
+	// IPV4 address of peer endpoint on remote site
+	PeerAddress string `json:"peer_address,omitempty"`
+	// Peer identifier used in IKE negotiation
+	PeerId string `json:"peer_id,omitempty"`
+}
+
+// IPSecVPNPeerEndpointListResult is a page of IPSec VPN peer endpoints.
+type IPSecVPNPeerEndpointListResult struct {
+	Results []IPSecVPNPeerEndpoint `json:"results"`
+	// Opaque cursor to be used for getting next page of records
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// IPSecVPNIKESessionStatus is the status of the IKE session of an IPSec VPN
+// session.
+type IPSecVPNIKESessionStatus struct {
+	// State of the IKE session, UP, DOWN or NEGOTIATING
+	IkeSessionState string `json:"ike_session_state,omitempty"`
+	// Reason for failure
+	FailReason string `json:"fail_reason,omitempty"`
+}
+
+// IPSecVPNTrafficCounters are the traffic counters of an IPSec VPN session or
+// policy.
+type IPSecVPNTrafficCounters struct {
+	// Total number of bytes received
+	BytesIn int64 `json:"bytes_in,omitempty"`
+	// Total number of bytes sent
+	BytesOut int64 `json:"bytes_out,omitempty"`
+	// Total number of packets received
+	PacketsIn int64 `json:"packets_in,omitempty"`
+	// Total number of packets sent
+	PacketsOut int64 `json:"packets_out,omitempty"`
+	// Total number of incoming packets dropped
+	DroppedPacketsIn int64 `json:"dropped_packets_in,omitempty"`
+	// Total number of outgoing packets dropped
+	DroppedPacketsOut int64 `json:"dropped_packets_out,omitempty"`
+}
+
+// IPSecVPNSessionStatus is the aggregated status of an IPSec VPN session.
+type IPSecVPNSessionStatus struct {
+	// UUID of vpn session
+	SessionId string `json:"session_id,omitempty"`
+	// Display name of vpn session
+	DisplayName string `json:"display_name,omitempty"`
+	// State of the session, UP, DOWN or DEGRADED
+	SessionStatus string                    `json:"session_status,omitempty"`
+	IkeStatus     *IPSecVPNIKESessionStatus `json:"ike_status,omitempty"`
+	// Total number of tunnels
+	TotalTunnels int64 `json:"total_tunnels,omitempty"`
+	// Number of negotiated tunnels
+	NegotiatedTunnels int64 `json:"negotiated_tunnels,omitempty"`
+	// Number of failed tunnels
+	FailedTunnels            int64                    `json:"failed_tunnels,omitempty"`
+	AggregateTrafficCounters *IPSecVPNTrafficCounters `json:"aggregate_traffic_counters,omitempty"`
+}
+
+// IPSecVPNTunnelTrafficStatistics are the status and traffic counters of a
+// tunnel of an IPSec VPN session.
+type IPSecVPNTunnelTrafficStatistics struct {
+	// Local subnet to which a tunnel belongs
+	LocalSubnet string `json:"local_subnet,omitempty"`
+	// Peer subnet to which a tunnel belongs
+	PeerSubnet string `json:"peer_subnet,omitempty"`
+	// State of the tunnel, UP or DOWN
+	TunnelStatus string `json:"tunnel_status,omitempty"`
+	// Reason for the tunnel being down
+	TunnelDownReason string `json:"tunnel_down_reason,omitempty"`
+	// Total number of bytes received
+	BytesIn int64 `json:"bytes_in,omitempty"`
+	// Total number of bytes sent
+	BytesOut int64 `json:"bytes_out,omitempty"`
+	// Total number of packets received
+	PacketsIn int64 `json:"packets_in,omitempty"`
+	// Total number of packets sent
+	PacketsOut int64 `json:"packets_out,omitempty"`
+	// Total number of incoming packets dropped
+	DroppedPacketsIn int64 `json:"dropped_packets_in,omitempty"`
+	// Total number of outgoing packets dropped
+	DroppedPacketsOut int64 `json:"dropped_packets_out,omitempty"`
+}
+
+// IPSecVPNPolicyTrafficStatistics are the traffic statistics of the tunnels
+// of a policy rule of an IPSec VPN session.
+type IPSecVPNPolicyTrafficStatistics struct {
+	// Policy identifier
+	PolicyId                 string                            `json:"policy_id,omitempty"`
+	AggregateTrafficCounters *IPSecVPNTrafficCounters          `json:"aggregate_traffic_counters,omitempty"`
+	TunnelStatistics         []IPSecVPNTunnelTrafficStatistics `json:"tunnel_statistics,omitempty"`
+}
+
+// IPSecVPNSessionStatistics are the traffic statistics of an IPSec VPN
+// session.
+type IPSecVPNSessionStatistics struct {
+	// UUID of vpn session
+	IpsecVpnSessionId string `json:"ipsec_vpn_session_id,omitempty"`
+	// Display name of vpn session
+	DisplayName              string                            `json:"display_name,omitempty"`
+	IkeStatus                *IPSecVPNIKESessionStatus         `json:"ike_status,omitempty"`
+	AggregateTrafficCounters *IPSecVPNTrafficCounters          `json:"aggregate_traffic_counters,omitempty"`
+	PolicyStatistics         []IPSecVPNPolicyTrafficStatistics `json:"policy_statistics,omitempty"`
+	// Set to true if the statistics of some transport nodes are missing
+	PartialStats bool `json:"partial_stats,omitempty"`
+}
@@ -0,0 +1,79 @@
+package vpn
+
+import "github.com/vmware/go-vmware-nsxt/common"
+
+// L2VPNSession is an L2VPN session stretching logical switches to a peer
+// site over transport tunnels.
+type L2VPNSession struct {
+	// Unique identifier of this resource
+	Id string `json:"id,omitempty"`
+	// Defaults to ID if not set
+	DisplayName string `json:"display_name,omitempty"`
+	// Enable to extend all the associated logical switches
+	Enabled bool `json:"enabled"`
+	// L2VPN service id
+	L2vpnServiceId string `json:"l2vpn_service_id,omitempty"`
+	// List of transport tunnels for redundancy
+	TransportTunnels []common.ResourceReference `json:"transport_tunnels,omitempty"`
+}
+
+// L2VPNSessionListResult is a page of L2VPN sessions.
+type L2VPNSessionListResult struct {
+	Results []L2VPNSession `json:"results"`
+	// Opaque cursor to be used for getting next page of records
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// L2VPNTransportTunnelStatus is the status of a transport tunnel of an L2VPN
+// session. IPSec transport tunnels report the status of their IPSec VPN
+// session.
+type L2VPNTransportTunnelStatus struct {
+	// IPSecVPNTransportStatus
+	ResourceType  string                    `json:"resource_type,omitempty"`
+	TunnelId      *common.ResourceReference `json:"tunnel_id,omitempty"`
+	SessionStatus *IPSecVPNSessionStatus    `json:"session_status,omitempty"`
+}
+
+// L2VPNSessionStatus is the status of an L2VPN session and its transport
+// tunnels.
+type L2VPNSessionStatus struct {
+	// Session identifier
+	SessionId string `json:"session_id,omitempty"`
+	// Display name of l2vpn session
+	DisplayName string `json:"display_name,omitempty"`
+	// State of the session, UP or DOWN
+	Status           string                       `json:"status,omitempty"`
+	TransportTunnels []L2VPNTransportTunnelStatus `json:"transport_tunnels,omitempty"`
+}
+
+// L2VPNPerLSTrafficStatistics are the traffic counters of a logical switch
+// stretched by an L2VPN session.
+type L2VPNPerLSTrafficStatistics struct {
+	LogicalSwitch *common.ResourceReference `json:"logical_switch,omitempty"`
+	// Total number of incoming bytes
+	BytesIn int64 `json:"bytes_in,omitempty"`
+	// Total number of outgoing bytes
+	BytesOut int64 `json:"bytes_out,omitempty"`
+	// Total number of incoming packets
+	PacketsIn int64 `json:"packets_in,omitempty"`
+	// Total number of outgoing packets
+	PacketsOut int64 `json:"packets_out,omitempty"`
+	// Total number of incoming Broadcast, Unknown unicast and Multicast (BUM) bytes
+	BumBytesIn int64 `json:"bum_bytes_in,omitempty"`
+	// Total number of outgoing Broadcast, Unknown unicast and Multicast (BUM) bytes
+	BumBytesOut int64 `json:"bum_bytes_out,omitempty"`
+	// Total number of incoming Broadcast, Unknown unicast and Multicast (BUM) packets
+	BumPacketsIn int64 `json:"bum_packets_in,omitempty"`
+	// Total number of outgoing Broadcast, Unknown unicast and Multicast (BUM) packets
+	BumPacketsOut int64 `json:"bum_packets_out,omitempty"`
+}
+
+// L2VPNSessionStatistics are the traffic statistics of an L2VPN session per
+// stretched logical switch.
+type L2VPNSessionStatistics struct {
+	// Session identifier
+	SessionId string `json:"session_id,omitempty"`
+	// Display name of l2vpn session
+	DisplayName                       string                        `json:"display_name,omitempty"`
+	TrafficStatisticsPerLogicalSwitch []L2VPNPerLSTrafficStatistics `json:"traffic_statistics_per_logical_switch,omitempty"`
+}
@@ -0,0 +1,186 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"nsxt_exporter/client"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vmware/go-vmware-nsxt/manager"
+)
+
+var (
+	bfdPeerPossibleState = [...]string{"UP", "DOWN", "INIT", "ADMIN_DOWN"}
+
+	// bfdDiagnosticCodes are the diagnostic codes of RFC 5880 by the
+	// diagnostic message reported by NSX-T.
+	bfdDiagnosticCodes = map[string]float64{
+		"NO_DIAGNOSTIC":                  0,
+		"CONTROL_DETECTION_TIME_EXPIRED": 1,
+		"ECHO_FUNCTION_FAILED":           2,
+		"NEIGHBOR_SIGNALED_SESSION_DOWN": 3,
+		"FORWARDING_PLANE_RESET":         4,
+		"PATH_DOWN":                      5,
+		"CONCATENATED_PATH_DOWN":         6,
+		"ADMINISTRATIVELY_DOWN":          7,
+		"REVERSE_CONCATENATED_PATH_DOWN": 8,
+	}
+)
+
+func init() {
+	registerCollector("bfd", defaultDisabled, createBFDCollectorFactory)
+}
+
+type bfdCollector struct {
+	logicalRouterClient client.LogicalRouterClient
+	logger              log.Logger
+
+	peerStatus              *prometheus.Desc
+	peerLocalDiagnostic     *prometheus.Desc
+	peerRemoteDiagnostic    *prometheus.Desc
+	peerLastStateChangeTime *prometheus.Desc
+}
+
+type bfdPeerMetric struct {
+	LogicalRouterID      string
+	LogicalRouterName    string
+	TransportNodeID      string
+	SourceAddress        string
+	PeerAddress          string
+	StateDetail          map[string]float64
+	LocalDiagnosticCode  float64
+	RemoteDiagnosticCode float64
+	LastStateChangeTime  float64
+}
+
+func createBFDCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
+	nsxtClient := client.NewNSXTClient(apiClient, logger)
+	return newBFDCollector(nsxtClient, logger)
+}
+
+func newBFDCollector(logicalRouterClient client.LogicalRouterClient, logger log.Logger) *bfdCollector {
+	labels := []string{"logical_router_id", "logical_router_name", "transport_node_id", "source_address", "peer_address"}
+	peerStatus := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "bfd_peer", "status"),
+		"State of BFD session of logical router with peer on transport node",
+		append(labels, "state"),
+		nil,
+	)
+	peerLocalDiagnostic := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "bfd_peer", "local_diagnostic_code"),
+		"RFC 5880 diagnostic code of the local end of BFD session, -1 if unknown",
+		labels,
+		nil,
+	)
+	peerRemoteDiagnostic := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "bfd_peer", "remote_diagnostic_code"),
+		"RFC 5880 diagnostic code of the remote end of BFD session, -1 if unknown",
+		labels,
+		nil,
+	)
+	peerLastStateChangeTime := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "bfd_peer", "last_state_change_timestamp_seconds"),
+		"Time of the last state change of BFD session in seconds since epoch",
+		labels,
+		nil,
+	)
+	return &bfdCollector{
+		logicalRouterClient:     logicalRouterClient,
+		logger:                  logger,
+		peerStatus:              peerStatus,
+		peerLocalDiagnostic:     peerLocalDiagnostic,
+		peerRemoteDiagnostic:    peerRemoteDiagnostic,
+		peerLastStateChangeTime: peerLastStateChangeTime,
+	}
+}
+
+// Describe implements the Collector interface.
+func (c *bfdCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.peerStatus
+	ch <- c.peerLocalDiagnostic
+	ch <- c.peerRemoteDiagnostic
+	ch <- c.peerLastStateChangeTime
+}
+
+// Update implements the Collector interface.
+func (c *bfdCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	logicalRouters, err := c.logicalRouterClient.ListAllLogicalRouters(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list logical routers: %s", err)
+	}
+	bfdPeerMetrics := c.generateBFDPeerMetrics(ctx, logicalRouters)
+	for _, m := range bfdPeerMetrics {
+		labels := []string{m.LogicalRouterID, m.LogicalRouterName, m.TransportNodeID, m.SourceAddress, m.PeerAddress}
+		for state, value := range m.StateDetail {
+			ch <- prometheus.MustNewConstMetric(c.peerStatus, prometheus.GaugeValue, value, append(labels, state)...)
+		}
+		ch <- prometheus.MustNewConstMetric(c.peerLocalDiagnostic, prometheus.GaugeValue, m.LocalDiagnosticCode, labels...)
+		ch <- prometheus.MustNewConstMetric(c.peerRemoteDiagnostic, prometheus.GaugeValue, m.RemoteDiagnosticCode, labels...)
+		if m.LastStateChangeTime > 0 {
+			ch <- prometheus.MustNewConstMetric(c.peerLastStateChangeTime, prometheus.GaugeValue, m.LastStateChangeTime, labels...)
+		}
+	}
+	return nil
+}
+
+// generateBFDPeerMetrics returns the BFD sessions of every logical router on
+// the transport nodes running its service router.
+func (c *bfdCollector) generateBFDPeerMetrics(ctx context.Context, logicalRouters []manager.LogicalRouter) (bfdPeerMetrics []bfdPeerMetric) {
+	routerNodes := listRouterNodes(ctx, "bfd", c.logicalRouterClient, logicalRouters, c.logger)
+	var mtx sync.Mutex
+	forEach(ctx, "bfd", len(routerNodes), func(i int) {
+		logicalRouter, transportNodeID := routerNodes[i].logicalRouter, routerNodes[i].transportNodeID
+		peersStatus, err := c.logicalRouterClient.ListAllBfdPeersStatus(ctx, logicalRouter.Id, transportNodeID)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "Unable to get BFD peers status", "id", logicalRouter.Id, "transportNodeID", transportNodeID, "err", err)
+			return
+		}
+		var nodeMetrics []bfdPeerMetric
+		for _, peerStatus := range peersStatus {
+			bfdPeerMetric := bfdPeerMetric{
+				LogicalRouterID:      logicalRouter.Id,
+				LogicalRouterName:    logicalRouter.DisplayName,
+				TransportNodeID:      transportNodeID,
+				SourceAddress:        peerStatus.SourceIpAddress,
+				PeerAddress:          peerStatus.PeerIpAddress,
+				StateDetail:          make(map[string]float64),
+				LocalDiagnosticCode:  bfdDiagnosticCode(peerStatus.Diagnostic),
+				RemoteDiagnosticCode: bfdDiagnosticCode(peerStatus.RemoteDiagnostic),
+				LastStateChangeTime:  float64(peerStatus.LastStateChangeTime) / 1000,
+			}
+			for _, state := range bfdPeerPossibleState {
+				stateValue := 0.0
+				if state == normalizeBFDValue(peerStatus.State) {
+					stateValue = 1.0
+				}
+				bfdPeerMetric.StateDetail[state] = stateValue
+			}
+			nodeMetrics = append(nodeMetrics, bfdPeerMetric)
+		}
+		mtx.Lock()
+		bfdPeerMetrics = append(bfdPeerMetrics, nodeMetrics...)
+		mtx.Unlock()
+	})
+	return
+}
+
+// bfdDiagnosticCode returns the RFC 5880 code of a diagnostic message, like
+// "Control Detection Time Expired", or -1 if the message is unknown. A
+// missing message means no diagnostic.
+func bfdDiagnosticCode(diagnostic string) float64 {
+	if diagnostic == "" {
+		return 0
+	}
+	if code, ok := bfdDiagnosticCodes[normalizeBFDValue(diagnostic)]; ok {
+		return code
+	}
+	return -1
+}
+
+func normalizeBFDValue(value string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_").Replace(strings.TrimSpace(value)))
+}
@@ -0,0 +1,165 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"nsxt_exporter/client/routing"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/go-vmware-nsxt/manager"
+)
+
+const (
+	fakeBFDLogicalRouterID = "fake-logical-router-id"
+	fakeBFDEdgeClusterID   = "fake-edge-cluster-id"
+	fakeBFDPeerAddress     = "192.168.0.1"
+	fakeBFDSourceAddress   = "192.168.0.2"
+)
+
+type mockBFDLogicalRouterClient struct {
+	transportNodes  map[string][]string
+	routerStatusErr error
+	responses       map[string]mockBFDResponse
+}
+
+type mockBFDResponse struct {
+	PeersStatus []routing.BfdPeerStatus
+	Error       error
+}
+
+func (c *mockBFDLogicalRouterClient) ListAllLogicalRouters(ctx context.Context) ([]manager.LogicalRouter, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockBFDLogicalRouterClient) GetLogicalRouterStatus(ctx context.Context, lrouterID string) (manager.LogicalRouterStatus, error) {
+	if c.routerStatusErr != nil {
+		return manager.LogicalRouterStatus{}, c.routerStatusErr
+	}
+	var perNodeStatus []manager.LogicalRouterStatusPerNode
+	for _, transportNodeID := range c.transportNodes[lrouterID] {
+		perNodeStatus = append(perNodeStatus, manager.LogicalRouterStatusPerNode{TransportNodeId: transportNodeID})
+	}
+	return manager.LogicalRouterStatus{LogicalRouterId: lrouterID, PerNodeStatus: perNodeStatus}, nil
+}
+
+func (c *mockBFDLogicalRouterClient) ListAllNatRules(ctx context.Context, lrouterID string) ([]manager.NatRule, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockBFDLogicalRouterClient) GetNatStatisticsPerRule(ctx context.Context, lrouterID, ruleID string) (manager.NatStatisticsPerRule, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockBFDLogicalRouterClient) ListAllBgpNeighborsStatus(ctx context.Context, lrouterID string) ([]manager.BgpNeighborStatus, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockBFDLogicalRouterClient) ListAllBfdPeersStatus(ctx context.Context, lrouterID, transportNodeID string) ([]routing.BfdPeerStatus, error) {
+	res, ok := c.responses[transportNodeID]
+	if !ok {
+		return nil, errors.New("error transport node not found")
+	}
+	return res.PeersStatus, res.Error
+}
+
+func (c *mockBFDLogicalRouterClient) ListAllRoutingTableRoutes(ctx context.Context, lrouterID, transportNodeID string) ([]manager.LogicalRouterRouteEntry, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockBFDLogicalRouterClient) ListAllForwardingTableRoutes(ctx context.Context, lrouterID, transportNodeID string) ([]manager.LogicalRouterRouteEntry, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func buildExpectedBFDPeerMetric(transportNodeID, sourceAddress, state string, localDiagnosticCode, remoteDiagnosticCode, lastStateChangeTime float64) bfdPeerMetric {
+	stateDetail := make(map[string]float64)
+	for _, possibleState := range bfdPeerPossibleState {
+		stateDetail[possibleState] = 0.0
+	}
+	stateDetail[state] = 1.0
+	return bfdPeerMetric{
+		LogicalRouterID:      fakeBFDLogicalRouterID,
+		LogicalRouterName:    fakeBFDLogicalRouterID + "-name",
+		TransportNodeID:      transportNodeID,
+		SourceAddress:        sourceAddress,
+		PeerAddress:          fakeBFDPeerAddress,
+		StateDetail:          stateDetail,
+		LocalDiagnosticCode:  localDiagnosticCode,
+		RemoteDiagnosticCode: remoteDiagnosticCode,
+		LastStateChangeTime:  lastStateChangeTime,
+	}
+}
+
+func TestBFDCollector_GenerateBFDPeerMetrics(t *testing.T) {
+	logicalRouters := []manager.LogicalRouter{
+		{Id: fakeBFDLogicalRouterID, DisplayName: fakeBFDLogicalRouterID + "-name", EdgeClusterId: fakeBFDEdgeClusterID},
+		{Id: "fake-distributed-router-id", DisplayName: "fake-distributed-router-name"},
+	}
+	testcases := []struct {
+		description     string
+		routerStatusErr error
+		responses       map[string]mockBFDResponse
+		expectedMetrics []bfdPeerMetric
+	}{
+		{
+			description: "Should return BFD peer metrics of every transport node",
+			responses: map[string]mockBFDResponse{
+				"edge-1": {PeersStatus: []routing.BfdPeerStatus{
+					{SourceIpAddress: fakeBFDSourceAddress, PeerIpAddress: fakeBFDPeerAddress, State: "UP", Diagnostic: "No Diagnostic", LastStateChangeTime: 1500000000000},
+				}},
+				"edge-2": {PeersStatus: []routing.BfdPeerStatus{
+					{SourceIpAddress: fakeBFDSourceAddress, PeerIpAddress: fakeBFDPeerAddress, State: "down", Diagnostic: "CONTROL_DETECTION_TIME_EXPIRED", RemoteDiagnostic: "Neighbor Signaled Session Down", LastStateChangeTime: 1600000000500},
+				}},
+			},
+			expectedMetrics: []bfdPeerMetric{
+				buildExpectedBFDPeerMetric("edge-1", fakeBFDSourceAddress, "UP", 0, 0, 1500000000),
+				buildExpectedBFDPeerMetric("edge-2", fakeBFDSourceAddress, "DOWN", 1, 3, 1600000000.5),
+			},
+		},
+		{
+			description: "Should only return BFD peer metrics with valid response",
+			responses: map[string]mockBFDResponse{
+				"edge-1": {Error: errors.New("error get BFD peers status")},
+				"edge-2": {PeersStatus: []routing.BfdPeerStatus{
+					{SourceIpAddress: fakeBFDSourceAddress, PeerIpAddress: fakeBFDPeerAddress, State: "ADMIN_DOWN", Diagnostic: "fake-unknown-diagnostic"},
+				}},
+			},
+			expectedMetrics: []bfdPeerMetric{
+				buildExpectedBFDPeerMetric("edge-2", fakeBFDSourceAddress, "ADMIN_DOWN", -1, 0, 0),
+			},
+		},
+		{
+			description: "Should return BFD peer metrics of every session to the same peer",
+			responses: map[string]mockBFDResponse{
+				"edge-1": {PeersStatus: []routing.BfdPeerStatus{
+					{SourceIpAddress: fakeBFDSourceAddress, PeerIpAddress: fakeBFDPeerAddress, State: "UP"},
+					{SourceIpAddress: "192.168.1.2", PeerIpAddress: fakeBFDPeerAddress, State: "DOWN"},
+				}},
+				"edge-2": {},
+			},
+			expectedMetrics: []bfdPeerMetric{
+				buildExpectedBFDPeerMetric("edge-1", fakeBFDSourceAddress, "UP", 0, 0, 0),
+				buildExpectedBFDPeerMetric("edge-1", "192.168.1.2", "DOWN", 0, 0, 0),
+			},
+		},
+		{
+			description:     "Should return empty metrics when logical router status fails",
+			routerStatusErr: errors.New("error get logical router status"),
+			responses:       map[string]mockBFDResponse{},
+			expectedMetrics: []bfdPeerMetric{},
+		},
+	}
+	for _, tc := range testcases {
+		mockBFDLogicalRouterClient := &mockBFDLogicalRouterClient{
+			transportNodes: map[string][]string{
+				fakeBFDLogicalRouterID: {"edge-1", "edge-2"},
+			},
+			routerStatusErr: tc.routerStatusErr,
+			responses:       tc.responses,
+		}
+		bfdCollector := newBFDCollector(mockBFDLogicalRouterClient, log.NewNopLogger())
+		bfdPeerMetrics := bfdCollector.generateBFDPeerMetrics(context.Background(), logicalRouters)
+		assert.ElementsMatch(t, tc.expectedMetrics, bfdPeerMetrics, tc.description)
+	}
+}
@@ -0,0 +1,209 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"nsxt_exporter/client"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vmware/go-vmware-nsxt/manager"
+)
+
+var bgpNeighborPossibleConnectionState = [...]string{"INVALID", "IDLE", "CONNECT", "ACTIVE", "OPEN_SENT", "OPEN_CONFIRM", "ESTABLISHED"}
+
+func init() {
+	registerCollector("bgp", defaultDisabled, createBGPCollectorFactory)
+}
+
+type bgpCollector struct {
+	logicalRouterClient client.LogicalRouterClient
+	logger              log.Logger
+
+	neighborStatus                 *prometheus.Desc
+	neighborEstablishedSeconds     *prometheus.Desc
+	neighborPrefixesReceived       *prometheus.Desc
+	neighborPrefixesAdvertised     *prometheus.Desc
+	neighborMessagesReceived       *prometheus.Desc
+	neighborMessagesSent           *prometheus.Desc
+	neighborEstablishedConnections *prometheus.Desc
+	neighborConnectionDrops        *prometheus.Desc
+}
+
+type bgpNeighborMetric struct {
+	LogicalRouterID        string
+	LogicalRouterName      string
+	TransportNodeID        string
+	NeighborAddress        string
+	ConnectionStateDetail  map[string]float64
+	EstablishedSeconds     float64
+	PrefixesReceived       float64
+	PrefixesAdvertised     float64
+	MessagesReceived       float64
+	MessagesSent           float64
+	EstablishedConnections float64
+	ConnectionDrops        float64
+}
+
+func createBGPCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
+	nsxtClient := client.NewNSXTClient(apiClient, logger)
+	return newBGPCollector(nsxtClient, logger)
+}
+
+func newBGPCollector(logicalRouterClient client.LogicalRouterClient, logger log.Logger) *bgpCollector {
+	labels := []string{"logical_router_id", "logical_router_name", "transport_node_id", "neighbor_address"}
+	neighborStatus := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "bgp_neighbor", "status"),
+		"Connection state of BGP neighbor of Tier-0 logical router on transport node",
+		append(labels, "connection_state"),
+		nil,
+	)
+	neighborEstablishedSeconds := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "bgp_neighbor", "established_seconds"),
+		"Time since the connection to BGP neighbor was established in seconds",
+		labels,
+		nil,
+	)
+	neighborPrefixesReceived := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "bgp_neighbor", "prefixes_received"),
+		"Number of prefixes received from BGP neighbor",
+		labels,
+		nil,
+	)
+	neighborPrefixesAdvertised := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "bgp_neighbor", "prefixes_advertised"),
+		"Number of prefixes advertised to BGP neighbor",
+		labels,
+		nil,
+	)
+	neighborMessagesReceived := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "bgp_neighbor", "messages_received"),
+		"Total messages received from BGP neighbor",
+		labels,
+		nil,
+	)
+	neighborMessagesSent := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "bgp_neighbor", "messages_sent"),
+		"Total messages sent to BGP neighbor",
+		labels,
+		nil,
+	)
+	neighborEstablishedConnections := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "bgp_neighbor", "established_connections"),
+		"Total transitions of the connection to BGP neighbor to established",
+		labels,
+		nil,
+	)
+	neighborConnectionDrops := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "bgp_neighbor", "connection_drops"),
+		"Total drops of the connection to BGP neighbor",
+		labels,
+		nil,
+	)
+	return &bgpCollector{
+		logicalRouterClient:            logicalRouterClient,
+		logger:                         logger,
+		neighborStatus:                 neighborStatus,
+		neighborEstablishedSeconds:     neighborEstablishedSeconds,
+		neighborPrefixesReceived:       neighborPrefixesReceived,
+		neighborPrefixesAdvertised:     neighborPrefixesAdvertised,
+		neighborMessagesReceived:       neighborMessagesReceived,
+		neighborMessagesSent:           neighborMessagesSent,
+		neighborEstablishedConnections: neighborEstablishedConnections,
+		neighborConnectionDrops:        neighborConnectionDrops,
+	}
+}
+
+// Describe implements the Collector interface.
+func (c *bgpCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.neighborStatus
+	ch <- c.neighborEstablishedSeconds
+	ch <- c.neighborPrefixesReceived
+	ch <- c.neighborPrefixesAdvertised
+	ch <- c.neighborMessagesReceived
+	ch <- c.neighborMessagesSent
+	ch <- c.neighborEstablishedConnections
+	ch <- c.neighborConnectionDrops
+}
+
+// Update implements the Collector interface.
+func (c *bgpCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	logicalRouters, err := c.logicalRouterClient.ListAllLogicalRouters(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list logical routers: %s", err)
+	}
+	bgpNeighborMetrics := c.generateBGPNeighborMetrics(ctx, logicalRouters)
+	for _, m := range bgpNeighborMetrics {
+		labels := []string{m.LogicalRouterID, m.LogicalRouterName, m.TransportNodeID, m.NeighborAddress}
+		for connectionState, value := range m.ConnectionStateDetail {
+			ch <- prometheus.MustNewConstMetric(c.neighborStatus, prometheus.GaugeValue, value, append(labels, connectionState)...)
+		}
+		ch <- prometheus.MustNewConstMetric(c.neighborEstablishedSeconds, prometheus.GaugeValue, m.EstablishedSeconds, labels...)
+		ch <- prometheus.MustNewConstMetric(c.neighborPrefixesReceived, prometheus.GaugeValue, m.PrefixesReceived, labels...)
+		ch <- prometheus.MustNewConstMetric(c.neighborPrefixesAdvertised, prometheus.GaugeValue, m.PrefixesAdvertised, labels...)
+		ch <- prometheus.MustNewConstMetric(c.neighborMessagesReceived, prometheus.GaugeValue, m.MessagesReceived, labels...)
+		ch <- prometheus.MustNewConstMetric(c.neighborMessagesSent, prometheus.GaugeValue, m.MessagesSent, labels...)
+		ch <- prometheus.MustNewConstMetric(c.neighborEstablishedConnections, prometheus.GaugeValue, m.EstablishedConnections, labels...)
+		ch <- prometheus.MustNewConstMetric(c.neighborConnectionDrops, prometheus.GaugeValue, m.ConnectionDrops, labels...)
+	}
+	return nil
+}
+
+// generateBGPNeighborMetrics returns the status of the BGP neighbors of every
+// Tier-0 logical router, the only routers running BGP.
+func (c *bgpCollector) generateBGPNeighborMetrics(ctx context.Context, logicalRouters []manager.LogicalRouter) (bgpNeighborMetrics []bgpNeighborMetric) {
+	var tier0Routers []manager.LogicalRouter
+	for _, logicalRouter := range logicalRouters {
+		if strings.ToUpper(logicalRouter.RouterType) == "TIER0" {
+			tier0Routers = append(tier0Routers, logicalRouter)
+		}
+	}
+	var mtx sync.Mutex
+	forEach(ctx, "bgp", len(tier0Routers), func(i int) {
+		logicalRouter := tier0Routers[i]
+		neighborsStatus, err := c.logicalRouterClient.ListAllBgpNeighborsStatus(ctx, logicalRouter.Id)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "Unable to get BGP neighbors status", "id", logicalRouter.Id, "err", err)
+			return
+		}
+		var routerMetrics []bgpNeighborMetric
+		for _, neighborStatus := range neighborsStatus {
+			// Neighbors are reported per transport node, without it the
+			// status of the same neighbor on several edges can't be told
+			// apart.
+			if neighborStatus.TransportNode == nil {
+				level.Debug(c.logger).Log("msg", "Skipping BGP neighbor status without transport node", "id", logicalRouter.Id, "neighbor", neighborStatus.NeighborAddress)
+				continue
+			}
+			bgpNeighborMetric := bgpNeighborMetric{
+				LogicalRouterID:        logicalRouter.Id,
+				LogicalRouterName:      logicalRouter.DisplayName,
+				TransportNodeID:        neighborStatus.TransportNode.TargetId,
+				NeighborAddress:        neighborStatus.NeighborAddress,
+				ConnectionStateDetail:  make(map[string]float64),
+				EstablishedSeconds:     float64(neighborStatus.TimeSinceEstablished) / 1000,
+				PrefixesReceived:       float64(neighborStatus.TotalInPrefixCount),
+				PrefixesAdvertised:     float64(neighborStatus.TotalOutPrefixCount),
+				MessagesReceived:       float64(neighborStatus.MessagesReceived),
+				MessagesSent:           float64(neighborStatus.MessagesSent),
+				EstablishedConnections: float64(neighborStatus.EstablishedConnectionCount),
+				ConnectionDrops:        float64(neighborStatus.ConnectionDropCount),
+			}
+			for _, connectionState := range bgpNeighborPossibleConnectionState {
+				stateValue := 0.0
+				if connectionState == strings.ToUpper(neighborStatus.ConnectionState) {
+					stateValue = 1.0
+				}
+				bgpNeighborMetric.ConnectionStateDetail[connectionState] = stateValue
+			}
+			routerMetrics = append(routerMetrics, bgpNeighborMetric)
+		}
+		mtx.Lock()
+		bgpNeighborMetrics = append(bgpNeighborMetrics, routerMetrics...)
+		mtx.Unlock()
+	})
+	return
+}
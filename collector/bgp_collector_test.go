@@ -0,0 +1,168 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"nsxt_exporter/client/routing"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/go-vmware-nsxt/common"
+	"github.com/vmware/go-vmware-nsxt/manager"
+)
+
+const (
+	fakeBGPNeighborAddress      = "192.168.0.1"
+	fakeBGPTransportNodeID      = "fake-transport-node-id"
+	fakeBGPLogicalRouterID      = "fake-logical-router-id"
+	fakeBGPOtherLogicalRouterID = "fake-other-logical-router-id"
+)
+
+type mockBGPLogicalRouterClient struct {
+	responses map[string]mockBGPResponse
+}
+
+type mockBGPResponse struct {
+	NeighborsStatus []manager.BgpNeighborStatus
+	Error           error
+}
+
+func (c *mockBGPLogicalRouterClient) ListAllLogicalRouters(ctx context.Context) ([]manager.LogicalRouter, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockBGPLogicalRouterClient) GetLogicalRouterStatus(ctx context.Context, lrouterID string) (manager.LogicalRouterStatus, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockBGPLogicalRouterClient) ListAllNatRules(ctx context.Context, lrouterID string) ([]manager.NatRule, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockBGPLogicalRouterClient) GetNatStatisticsPerRule(ctx context.Context, lrouterID, ruleID string) (manager.NatStatisticsPerRule, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockBGPLogicalRouterClient) ListAllBgpNeighborsStatus(ctx context.Context, lrouterID string) ([]manager.BgpNeighborStatus, error) {
+	res, ok := c.responses[lrouterID]
+	if !ok {
+		return nil, errors.New("error logical router not found")
+	}
+	return res.NeighborsStatus, res.Error
+}
+
+func (c *mockBGPLogicalRouterClient) ListAllBfdPeersStatus(ctx context.Context, lrouterID, transportNodeID string) ([]routing.BfdPeerStatus, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockBGPLogicalRouterClient) ListAllRoutingTableRoutes(ctx context.Context, lrouterID, transportNodeID string) ([]manager.LogicalRouterRouteEntry, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockBGPLogicalRouterClient) ListAllForwardingTableRoutes(ctx context.Context, lrouterID, transportNodeID string) ([]manager.LogicalRouterRouteEntry, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func buildBGPNeighborStatus(connectionState string, value int64) manager.BgpNeighborStatus {
+	return manager.BgpNeighborStatus{
+		ConnectionState:            connectionState,
+		NeighborAddress:            fakeBGPNeighborAddress,
+		TimeSinceEstablished:       value * 1000,
+		TotalInPrefixCount:         value + 1,
+		TotalOutPrefixCount:        value + 2,
+		MessagesReceived:           value + 3,
+		MessagesSent:               value + 4,
+		EstablishedConnectionCount: value + 5,
+		ConnectionDropCount:        value + 6,
+		TransportNode:              &common.ResourceReference{TargetId: fakeBGPTransportNodeID},
+	}
+}
+
+func buildExpectedBGPNeighborMetric(lrouterID, connectionState string, value float64) bgpNeighborMetric {
+	connectionStateDetail := make(map[string]float64)
+	for _, state := range bgpNeighborPossibleConnectionState {
+		connectionStateDetail[state] = 0.0
+	}
+	connectionStateDetail[connectionState] = 1.0
+	return bgpNeighborMetric{
+		LogicalRouterID:        lrouterID,
+		LogicalRouterName:      lrouterID + "-name",
+		TransportNodeID:        fakeBGPTransportNodeID,
+		NeighborAddress:        fakeBGPNeighborAddress,
+		ConnectionStateDetail:  connectionStateDetail,
+		EstablishedSeconds:     value,
+		PrefixesReceived:       value + 1,
+		PrefixesAdvertised:     value + 2,
+		MessagesReceived:       value + 3,
+		MessagesSent:           value + 4,
+		EstablishedConnections: value + 5,
+		ConnectionDrops:        value + 6,
+	}
+}
+
+func TestBGPCollector_GenerateBGPNeighborMetrics(t *testing.T) {
+	logicalRouters := []manager.LogicalRouter{
+		{Id: fakeBGPLogicalRouterID, DisplayName: fakeBGPLogicalRouterID + "-name", RouterType: "TIER0"},
+		{Id: fakeBGPOtherLogicalRouterID, DisplayName: fakeBGPOtherLogicalRouterID + "-name", RouterType: "TIER0"},
+		{Id: "fake-tier1-router-id", DisplayName: "fake-tier1-router-name", RouterType: "TIER1"},
+	}
+	testcases := []struct {
+		description     string
+		responses       map[string]mockBGPResponse
+		expectedMetrics []bgpNeighborMetric
+	}{
+		{
+			description: "Should return BGP neighbor metrics of every Tier-0 router",
+			responses: map[string]mockBGPResponse{
+				fakeBGPLogicalRouterID: {NeighborsStatus: []manager.BgpNeighborStatus{
+					buildBGPNeighborStatus("ESTABLISHED", 10),
+				}},
+				fakeBGPOtherLogicalRouterID: {NeighborsStatus: []manager.BgpNeighborStatus{
+					buildBGPNeighborStatus("active", 0),
+				}},
+			},
+			expectedMetrics: []bgpNeighborMetric{
+				buildExpectedBGPNeighborMetric(fakeBGPLogicalRouterID, "ESTABLISHED", 10),
+				buildExpectedBGPNeighborMetric(fakeBGPOtherLogicalRouterID, "ACTIVE", 0),
+			},
+		},
+		{
+			description: "Should only return BGP neighbor metrics with valid response",
+			responses: map[string]mockBGPResponse{
+				fakeBGPLogicalRouterID: {Error: errors.New("error get BGP neighbors status")},
+				fakeBGPOtherLogicalRouterID: {NeighborsStatus: []manager.BgpNeighborStatus{
+					buildBGPNeighborStatus("IDLE", 0),
+				}},
+			},
+			expectedMetrics: []bgpNeighborMetric{
+				buildExpectedBGPNeighborMetric(fakeBGPOtherLogicalRouterID, "IDLE", 0),
+			},
+		},
+		{
+			description: "Should skip BGP neighbor status without transport node",
+			responses: map[string]mockBGPResponse{
+				fakeBGPLogicalRouterID: {NeighborsStatus: []manager.BgpNeighborStatus{
+					buildBGPNeighborStatus("ESTABLISHED", 10),
+					{ConnectionState: "ESTABLISHED", NeighborAddress: fakeBGPNeighborAddress},
+				}},
+			},
+			expectedMetrics: []bgpNeighborMetric{
+				buildExpectedBGPNeighborMetric(fakeBGPLogicalRouterID, "ESTABLISHED", 10),
+			},
+		},
+		{
+			description:     "Should return empty metrics when empty response",
+			responses:       map[string]mockBGPResponse{},
+			expectedMetrics: []bgpNeighborMetric{},
+		},
+	}
+	for _, tc := range testcases {
+		mockBGPLogicalRouterClient := &mockBGPLogicalRouterClient{
+			responses: tc.responses,
+		}
+		bgpCollector := newBGPCollector(mockBGPLogicalRouterClient, log.NewNopLogger())
+		bgpNeighborMetrics := bgpCollector.generateBGPNeighborMetrics(context.Background(), logicalRouters)
+		assert.ElementsMatch(t, tc.expectedMetrics, bgpNeighborMetrics, tc.description)
+	}
+}
@@ -1,53 +1,183 @@
 package collector
 
 import (
+	"context"
+	"fmt"
+	"nsxt_exporter/client"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
-	nsxt "github.com/vmware/go-vmware-nsxt"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
 )
 
 const (
-	namespace = "nsxt"
+	namespace       = "nsxt"
+	defaultEnabled  = true
+	defaultDisabled = false
 )
 
 var (
-	factories = make(map[string]func(client *nsxt.APIClient, logger log.Logger) prometheus.Collector)
+	scrapeDurationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "scrape", "collector_duration_seconds"),
+		"nsxt_exporter: Duration of a collector scrape.",
+		[]string{"collector"},
+		nil,
+	)
+	scrapeSuccessDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "scrape", "collector_success"),
+		"nsxt_exporter: Whether a collector succeeded.",
+		[]string{"collector"},
+		nil,
+	)
+	scrapeTimeoutDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "scrape", "collector_timeout"),
+		"nsxt_exporter: Whether a collector ran out of time before it completed.",
+		[]string{"collector"},
+		nil,
+	)
 )
 
-func registerCollector(collector string, factory func(client *nsxt.APIClient, logger log.Logger) prometheus.Collector) {
+var (
+	factories           = make(map[string]func(apiClient *client.APIClient, logger log.Logger) Collector)
+	collectorState      = make(map[string]*bool)
+	pollIntervals       = make(map[string]*time.Duration)
+	defaultPollInterval = kingpin.Flag("collector.poll-interval", "Interval to run collectors in the background and serve their last metrics. Collectors run on every scrape if 0.").Default("0s").Duration()
+)
+
+// Collector is the interface a collector has to implement.
+type Collector interface {
+	// Describe sends the descriptors of all metrics the collector may export.
+	Describe(ch chan<- *prometheus.Desc)
+	// Update sends the current metrics and returns an error if the collection
+	// failed. It stops querying the NSX-T manager once ctx is done.
+	Update(ctx context.Context, ch chan<- prometheus.Metric) error
+}
+
+func registerCollector(collector string, isDefaultEnabled bool, factory func(apiClient *client.APIClient, logger log.Logger) Collector) {
+	helpDefaultState := "disabled"
+	if isDefaultEnabled {
+		helpDefaultState = "enabled"
+	}
+	flagName := fmt.Sprintf("collector.%s", collector)
+	flagHelp := fmt.Sprintf("Enable the %s collector (default: %s).", collector, helpDefaultState)
+	defaultValue := fmt.Sprintf("%v", isDefaultEnabled)
+	collectorState[collector] = kingpin.Flag(flagName, flagHelp).Default(defaultValue).Bool()
+	pollFlagName := fmt.Sprintf("collector.%s.poll-interval", collector)
+	pollFlagHelp := fmt.Sprintf("Interval to run the %s collector in the background. Overrides --collector.poll-interval if not 0.", collector)
+	pollIntervals[collector] = kingpin.Flag(pollFlagName, pollFlagHelp).Default("0s").Duration()
+	concurrencyFlagName := fmt.Sprintf("collector.%s.max-concurrency", collector)
+	concurrencyFlagHelp := fmt.Sprintf("Maximum number of concurrent per-object requests of the %s collector. Only --collector.max-concurrency applies if 0.", collector)
+	concurrencyLimits[collector] = kingpin.Flag(concurrencyFlagName, concurrencyFlagHelp).Default("0").Int()
 	factories[collector] = factory
 }
 
+// pollInterval returns the interval to poll the collector with the given
+// name in the background, or 0 if it runs on every scrape.
+func pollInterval(collector string) time.Duration {
+	if interval := *pollIntervals[collector]; interval > 0 {
+		return interval
+	}
+	return *defaultPollInterval
+}
+
+// EnabledCollectors returns the sorted names of the collectors enabled by flags.
+func EnabledCollectors() []string {
+	var enabled []string
+	for collector, state := range collectorState {
+		if *state {
+			enabled = append(enabled, collector)
+		}
+	}
+	sort.Strings(enabled)
+	return enabled
+}
+
 // nsxtCollector collects NSX-T stats from the given api server and exports them using
 // the prometheus metrics package.
 type nsxtCollector struct {
-	collectors []prometheus.Collector
-	client     *nsxt.APIClient
+	ctx        context.Context
+	collectors map[string]Collector
+	poller     *Poller
+	polled     []string
+	apiClient  *client.APIClient
 	logger     log.Logger
 }
 
-// NewNSXTCollector creates a new NSXTCollector.
-func NewNSXTCollector(client *nsxt.APIClient, logger log.Logger) prometheus.Collector {
-	var collectors []prometheus.Collector
-	for key, factory := range factories {
-		collector := factory(client, log.With(logger, "collector", key))
-		collectors = append(collectors, collector)
+// NewNSXTCollector creates a new NSXTCollector. If filters are given, only the
+// collectors with the given names are created, regardless of whether they are
+// enabled by flags. Otherwise all enabled collectors are created. Collectors
+// stop querying the NSX-T manager once ctx is done and export the metrics
+// collected so far. Collectors run by the poller, which may be nil, export the
+// metrics of their last run instead.
+func NewNSXTCollector(ctx context.Context, apiClient *client.APIClient, poller *Poller, logger log.Logger, filters ...string) (prometheus.Collector, error) {
+	names, err := selectCollectors(filters...)
+	if err != nil {
+		return nil, err
 	}
-	return &nsxtCollector{
-		collectors: collectors,
-		client:     client,
+	n := &nsxtCollector{
+		ctx:        ctx,
+		collectors: make(map[string]Collector),
+		poller:     poller,
+		apiClient:  apiClient,
 		logger:     logger,
 	}
+	for _, name := range names {
+		if poller.polls(name) {
+			n.polled = append(n.polled, name)
+			continue
+		}
+		n.collectors[name] = factories[name](apiClient, log.With(logger, "collector", name))
+	}
+	return n, nil
+}
+
+// selectCollectors returns the names of the given collectors, or of the
+// collectors enabled by flags if none are given.
+func selectCollectors(filters ...string) ([]string, error) {
+	if err := CheckCollectors(filters...); err != nil {
+		return nil, err
+	}
+	if len(filters) == 0 {
+		return EnabledCollectors(), nil
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, filter := range filters {
+		if !seen[filter] {
+			seen[filter] = true
+			names = append(names, filter)
+		}
+	}
+	return names, nil
+}
+
+// CheckCollectors returns an error if any of the given names is not a
+// registered collector.
+func CheckCollectors(names ...string) error {
+	for _, name := range names {
+		if _, ok := factories[name]; !ok {
+			return fmt.Errorf("missing collector: %s", name)
+		}
+	}
+	return nil
 }
 
 // Describe implements the prometheus.Collector interface.
 func (n *nsxtCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- scrapeDurationDesc
+	ch <- scrapeSuccessDesc
+	ch <- scrapeTimeoutDesc
+	if len(n.polled) > 0 {
+		n.poller.describe(ch, n.polled...)
+	}
 	wg := sync.WaitGroup{}
 	wg.Add(len(n.collectors))
 	for _, c := range n.collectors {
-		go func(c prometheus.Collector) {
+		go func(c Collector) {
 			c.Describe(ch)
 			wg.Done()
 		}(c)
@@ -57,13 +187,45 @@ func (n *nsxtCollector) Describe(ch chan<- *prometheus.Desc) {
 
 // Collect implements the prometheus.Collector interface.
 func (n *nsxtCollector) Collect(ch chan<- prometheus.Metric) {
+	if len(n.polled) > 0 {
+		n.poller.collect(ch, n.polled...)
+	}
 	wg := sync.WaitGroup{}
 	wg.Add(len(n.collectors))
-	for _, c := range n.collectors {
-		go func(c prometheus.Collector) {
-			c.Collect(ch)
+	for name, c := range n.collectors {
+		go func(name string, c Collector) {
+			execute(n.ctx, name, c, ch, n.logger)
 			wg.Done()
-		}(c)
+		}(name, c)
 	}
 	wg.Wait()
 }
+
+// execute runs the collector and reports whether it succeeded.
+func execute(ctx context.Context, name string, c Collector, ch chan<- prometheus.Metric, logger log.Logger) bool {
+	begin := time.Now()
+	err := c.Update(ctx, ch)
+	duration := time.Since(begin)
+	var success, timeout float64
+
+	if ctx.Err() == context.DeadlineExceeded {
+		level.Warn(logger).Log("msg", "collector timed out, exporting partial metrics", "name", name, "duration_seconds", duration.Seconds())
+		timeout = 1
+	}
+	if err == nil {
+		// Collectors skip objects they failed to query, so a collector that
+		// ran out of time may have exported only part of its metrics.
+		err = ctx.Err()
+	}
+	if err != nil {
+		level.Error(logger).Log("msg", "collector failed", "name", name, "duration_seconds", duration.Seconds(), "err", err)
+		success = 0
+	} else {
+		level.Debug(logger).Log("msg", "collector succeeded", "name", name, "duration_seconds", duration.Seconds())
+		success = 1
+	}
+	ch <- prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, duration.Seconds(), name)
+	ch <- prometheus.MustNewConstMetric(scrapeSuccessDesc, prometheus.GaugeValue, success, name)
+	ch <- prometheus.MustNewConstMetric(scrapeTimeoutDesc, prometheus.GaugeValue, timeout, name)
+	return err == nil
+}
@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCollector struct {
+	err error
+}
+
+func (c *mockCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+func (c *mockCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return c.err
+}
+
+func TestNSXTCollector_CollectScrapeSuccess(t *testing.T) {
+	expiredCtx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	testcases := []struct {
+		description     string
+		ctx             context.Context
+		collectors      map[string]Collector
+		expectedMetrics string
+	}{
+		{
+			description: "Should report success of each collector",
+			ctx:         context.Background(),
+			collectors: map[string]Collector{
+				"succeeding": &mockCollector{},
+				"failing":    &mockCollector{err: errors.New("error list objects")},
+			},
+			expectedMetrics: `
+# HELP nsxt_scrape_collector_success nsxt_exporter: Whether a collector succeeded.
+# TYPE nsxt_scrape_collector_success gauge
+nsxt_scrape_collector_success{collector="failing"} 0
+nsxt_scrape_collector_success{collector="succeeding"} 1
+# HELP nsxt_scrape_collector_timeout nsxt_exporter: Whether a collector ran out of time before it completed.
+# TYPE nsxt_scrape_collector_timeout gauge
+nsxt_scrape_collector_timeout{collector="failing"} 0
+nsxt_scrape_collector_timeout{collector="succeeding"} 0
+`,
+		},
+		{
+			description: "Should report failure and timeout when the scrape timed out",
+			ctx:         expiredCtx,
+			collectors: map[string]Collector{
+				"succeeding": &mockCollector{},
+			},
+			expectedMetrics: `
+# HELP nsxt_scrape_collector_success nsxt_exporter: Whether a collector succeeded.
+# TYPE nsxt_scrape_collector_success gauge
+nsxt_scrape_collector_success{collector="succeeding"} 0
+# HELP nsxt_scrape_collector_timeout nsxt_exporter: Whether a collector ran out of time before it completed.
+# TYPE nsxt_scrape_collector_timeout gauge
+nsxt_scrape_collector_timeout{collector="succeeding"} 1
+`,
+		},
+		{
+			description:     "Should report nothing when there's no collector",
+			ctx:             context.Background(),
+			collectors:      map[string]Collector{},
+			expectedMetrics: ``,
+		},
+	}
+	for _, tc := range testcases {
+		nsxtCollector := &nsxtCollector{
+			ctx:        tc.ctx,
+			collectors: tc.collectors,
+			logger:     log.NewNopLogger(),
+		}
+		err := testutil.CollectAndCompare(nsxtCollector, strings.NewReader(tc.expectedMetrics), "nsxt_scrape_collector_success", "nsxt_scrape_collector_timeout")
+		assert.NoError(t, err, tc.description)
+	}
+}
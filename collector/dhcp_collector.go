@@ -1,20 +1,22 @@
 package collector
 
 import (
+	"context"
+	"fmt"
 	"nsxt_exporter/client"
 	"strings"
+	"sync"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
-	nsxt "github.com/vmware/go-vmware-nsxt"
 	"github.com/vmware/go-vmware-nsxt/manager"
 )
 
 var dhcpPossibleStatus = [...]string{"UP", "DOWN", "ERROR", "NO_STANDBY"}
 
 func init() {
-	registerCollector("dhcp", createDHCPCollectorFactory)
+	registerCollector("dhcp", defaultEnabled, createDHCPCollectorFactory)
 }
 
 type dhcpCollector struct {
@@ -47,7 +49,7 @@ type dhcpStatisticMetric struct {
 	Statistic manager.DhcpStatistics
 }
 
-func createDHCPCollectorFactory(apiClient *nsxt.APIClient, logger log.Logger) prometheus.Collector {
+func createDHCPCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
 	nsxtClient := client.NewNSXTClient(apiClient, logger)
 	return newDHCPCollector(nsxtClient, logger)
 }
@@ -143,7 +145,7 @@ func newDHCPCollector(dhcpClient client.DHCPClient, logger log.Logger) *dhcpColl
 	}
 }
 
-// Describe implements the prometheus.Collector interface.
+// Describe implements the Collector interface.
 func (dc *dhcpCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- dc.dhcpStatus
 	ch <- dc.dhcpAckPacket
@@ -159,20 +161,19 @@ func (dc *dhcpCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- dc.dhcpIPPoolAllocated
 }
 
-// Collect implements the prometheus.Collector interface.
-func (dc *dhcpCollector) Collect(ch chan<- prometheus.Metric) {
-	dhcpServers, err := dc.dhcpClient.ListAllDHCPServers()
+// Update implements the Collector interface.
+func (dc *dhcpCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	dhcpServers, err := dc.dhcpClient.ListAllDHCPServers(ctx)
 	if err != nil {
-		level.Error(dc.logger).Log("msg", "Unable to list dhcp servers", "err", err)
-		return
+		return fmt.Errorf("unable to list dhcp servers: %s", err)
 	}
-	dhcpStatusMetrics := dc.generateDHCPStatusMetrics(dhcpServers)
+	dhcpStatusMetrics := dc.generateDHCPStatusMetrics(ctx, dhcpServers)
 	for _, m := range dhcpStatusMetrics {
 		for status, value := range m.StatusDetail {
 			ch <- prometheus.MustNewConstMetric(dc.dhcpStatus, prometheus.GaugeValue, value, m.ID, m.Name, status)
 		}
 	}
-	dhcpStatisticMetrics := dc.generateDHCPStatisticMetrics(dhcpServers)
+	dhcpStatisticMetrics := dc.generateDHCPStatisticMetrics(ctx, dhcpServers)
 	for _, m := range dhcpStatisticMetrics {
 		dhcpLabels := []string{m.ID, m.Name}
 		ch <- prometheus.MustNewConstMetric(dc.dhcpAckPacket, prometheus.GaugeValue, float64(m.Statistic.Acks), dhcpLabels...)
@@ -190,14 +191,17 @@ func (dc *dhcpCollector) Collect(ch chan<- prometheus.Metric) {
 			ch <- prometheus.MustNewConstMetric(dc.dhcpIPPoolAllocated, prometheus.GaugeValue, float64(ipPoolStat.AllocatedNumber), ipPoolLabels...)
 		}
 	}
+	return nil
 }
 
-func (dc *dhcpCollector) generateDHCPStatusMetrics(dhcpServers []manager.LogicalDhcpServer) (dhcpStatusMetrics []dhcpStatusMetric) {
-	for _, dhcp := range dhcpServers {
-		dhcpStatus, err := dc.dhcpClient.GetDhcpStatus(dhcp.Id, nil)
+func (dc *dhcpCollector) generateDHCPStatusMetrics(ctx context.Context, dhcpServers []manager.LogicalDhcpServer) (dhcpStatusMetrics []dhcpStatusMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "dhcp", len(dhcpServers), func(i int) {
+		dhcp := dhcpServers[i]
+		dhcpStatus, err := dc.dhcpClient.GetDhcpStatus(ctx, dhcp.Id, nil)
 		if err != nil {
 			level.Error(dc.logger).Log("msg", "Unable to get dhcp status", "id", dhcp.Id, "err", err)
-			continue
+			return
 		}
 		dhcpStatusMetric := dhcpStatusMetric{
 			Name: dhcp.DisplayName,
@@ -211,24 +215,30 @@ func (dc *dhcpCollector) generateDHCPStatusMetrics(dhcpServers []manager.Logical
 			}
 			dhcpStatusMetric.StatusDetail[status] = statusValue
 		}
+		mtx.Lock()
 		dhcpStatusMetrics = append(dhcpStatusMetrics, dhcpStatusMetric)
-	}
+		mtx.Unlock()
+	})
 	return
 }
 
-func (dc *dhcpCollector) generateDHCPStatisticMetrics(dhcpServers []manager.LogicalDhcpServer) (dhcpStatisticMetrics []dhcpStatisticMetric) {
-	for _, dhcp := range dhcpServers {
-		dhcpStatistic, err := dc.dhcpClient.GetDHCPStatistic(dhcp.Id)
+func (dc *dhcpCollector) generateDHCPStatisticMetrics(ctx context.Context, dhcpServers []manager.LogicalDhcpServer) (dhcpStatisticMetrics []dhcpStatisticMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "dhcp", len(dhcpServers), func(i int) {
+		dhcp := dhcpServers[i]
+		dhcpStatistic, err := dc.dhcpClient.GetDHCPStatistic(ctx, dhcp.Id)
 		if err != nil {
 			level.Error(dc.logger).Log("msg", "Unable to get dhcp statistic", "id", dhcp.Id, "err", err)
-			continue
+			return
 		}
 		dhcpStatisticMetric := dhcpStatisticMetric{
 			ID:        dhcp.Id,
 			Name:      dhcp.DisplayName,
 			Statistic: dhcpStatistic,
 		}
+		mtx.Lock()
 		dhcpStatisticMetrics = append(dhcpStatisticMetrics, dhcpStatisticMetric)
-	}
+		mtx.Unlock()
+	})
 	return
 }
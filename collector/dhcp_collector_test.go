@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -28,11 +29,11 @@ type mockDHCPResponse struct {
 	Statistics  manager.DhcpStatistics
 }
 
-func (c *mockDHCPClient) ListAllDHCPServers() ([]manager.LogicalDhcpServer, error) {
+func (c *mockDHCPClient) ListAllDHCPServers(ctx context.Context) ([]manager.LogicalDhcpServer, error) {
 	panic("unused function. Only used to satisfy DHCPClient interface")
 }
 
-func (c *mockDHCPClient) GetDhcpStatus(dhcpID string, localVarOptionals map[string]interface{}) (manager.DhcpServerStatus, error) {
+func (c *mockDHCPClient) GetDhcpStatus(ctx context.Context, dhcpID string, localVarOptionals map[string]interface{}) (manager.DhcpServerStatus, error) {
 	for _, res := range c.responses {
 		if res.ID == dhcpID {
 			return manager.DhcpServerStatus{
@@ -43,7 +44,7 @@ func (c *mockDHCPClient) GetDhcpStatus(dhcpID string, localVarOptionals map[stri
 	return manager.DhcpServerStatus{}, errors.New("error")
 }
 
-func (c *mockDHCPClient) GetDHCPStatistic(dhcpID string) (manager.DhcpStatistics, error) {
+func (c *mockDHCPClient) GetDHCPStatistic(ctx context.Context, dhcpID string) (manager.DhcpStatistics, error) {
 	for _, res := range c.responses {
 		if res.ID == dhcpID {
 			return res.Statistics, res.Error
@@ -199,7 +200,7 @@ func TestDHCPCollector_GenerateDHCPStatisticMetrics(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		dhcpCollector := newDHCPCollector(mockDHCPClient, logger)
-		dhcpMetrics := dhcpCollector.generateDHCPStatisticMetrics(dhcpServers)
+		dhcpMetrics := dhcpCollector.generateDHCPStatisticMetrics(context.Background(), dhcpServers)
 		assert.ElementsMatch(t, tc.expectedMetrics, dhcpMetrics, tc.description)
 	}
 }
@@ -314,7 +315,7 @@ func TestDHCPCollector_GenerateDHCPStatusMetrics(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		dhcpCollector := newDHCPCollector(mockDHCPClient, logger)
-		dhcpMetrics := dhcpCollector.generateDHCPStatusMetrics(dhcpServers)
+		dhcpMetrics := dhcpCollector.generateDHCPStatusMetrics(context.Background(), dhcpServers)
 		assert.ElementsMatch(t, tc.expectedMetrics, dhcpMetrics, tc.description)
 	}
 }
@@ -1,17 +1,19 @@
 package collector
 
 import (
+	"context"
+	"fmt"
 	"nsxt_exporter/client"
+	"strings"
+	"sync"
 
 	"github.com/go-kit/kit/log"
-	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
-	nsxt "github.com/vmware/go-vmware-nsxt"
 	"github.com/vmware/go-vmware-nsxt/manager"
 )
 
 func init() {
-	registerCollector("firewall", createFirewallCollectorFactory)
+	registerCollector("firewall", defaultDisabled, createFirewallCollectorFactory)
 }
 
 type firewallCollector struct {
@@ -30,7 +32,7 @@ type firewallStatisticMetric struct {
 	TotalBytes   float64
 }
 
-func createFirewallCollectorFactory(apiClient *nsxt.APIClient, logger log.Logger) prometheus.Collector {
+func createFirewallCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
 	nsxtClient := client.NewNSXTClient(apiClient, logger)
 	return newFirewallCollector(nsxtClient, logger)
 }
@@ -56,48 +58,72 @@ func newFirewallCollector(firewallClient client.FirewallClient, logger log.Logge
 	}
 }
 
-// Describe implements the prometheus.Collector interface.
+// Describe implements the Collector interface.
 func (c *firewallCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.totalPackets
 	ch <- c.totalBytes
 }
 
-// Collect implements the prometheus.Collector interface.
-func (c *firewallCollector) Collect(ch chan<- prometheus.Metric) {
-	firewallSections, err := c.firewallClient.ListAllFirewallSections()
+// Update implements the Collector interface.
+func (c *firewallCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	firewallSections, err := c.firewallClient.ListAllFirewallSections(ctx)
 	if err != nil {
-		level.Error(c.logger).Log("msg", "Unable to list firewall sections", "err", err)
+		return fmt.Errorf("unable to list firewall sections: %s", err)
 	}
-	firewallStatisticMetrics := c.generateFirewallStatisticMetrics(firewallSections)
+	firewallStatisticMetrics, err := c.generateFirewallStatisticMetrics(ctx, firewallSections)
 	for _, m := range firewallStatisticMetrics {
 		labels := []string{m.RuleID, m.RuleName, m.SectionID}
 		ch <- prometheus.MustNewConstMetric(c.totalPackets, prometheus.GaugeValue, m.TotalPackets, labels...)
 		ch <- prometheus.MustNewConstMetric(c.totalBytes, prometheus.GaugeValue, m.TotalBytes, labels...)
 	}
+	return err
 }
 
-func (c *firewallCollector) generateFirewallStatisticMetrics(firewallSections []manager.FirewallSection) (firewallStatisticMetrics []firewallStatisticMetric) {
-	for _, sec := range firewallSections {
-		rules, err := c.firewallClient.GetAllFirewallRules(sec.Id)
+func (c *firewallCollector) generateFirewallStatisticMetrics(ctx context.Context, firewallSections []manager.FirewallSection) (firewallStatisticMetrics []firewallStatisticMetric, err error) {
+	type sectionRule struct {
+		section manager.FirewallSection
+		rule    manager.FirewallRule
+	}
+	var mtx sync.Mutex
+	var errs []string
+	var sectionRules []sectionRule
+	forEach(ctx, "firewall", len(firewallSections), func(i int) {
+		sec := firewallSections[i]
+		rules, err := c.firewallClient.GetAllFirewallRules(ctx, sec.Id)
 		if err != nil {
-			level.Error(c.logger).Log("msg", "Unable to get firewall rules", "section", sec.Id, "err", err)
-			continue
+			mtx.Lock()
+			errs = append(errs, fmt.Sprintf("unable to get firewall rules of section %s: %s", sec.Id, err))
+			mtx.Unlock()
+			return
 		}
+		mtx.Lock()
 		for _, rule := range rules {
-			stats, err := c.firewallClient.GetFirewallStats(sec.Id, rule.Id)
-			if err != nil {
-				level.Error(c.logger).Log("msg", "Unable to get firewall statistic", "section", sec.Id, "rule", rule.Id, "err", err)
-				continue
-			}
-			firewallStatisticMetric := firewallStatisticMetric{
-				SectionID:    sec.Id,
-				RuleID:       rule.Id,
-				RuleName:     rule.DisplayName,
-				TotalPackets: float64(stats.PacketCount),
-				TotalBytes:   float64(stats.ByteCount),
-			}
-			firewallStatisticMetrics = append(firewallStatisticMetrics, firewallStatisticMetric)
+			sectionRules = append(sectionRules, sectionRule{section: sec, rule: rule})
+		}
+		mtx.Unlock()
+	})
+	forEach(ctx, "firewall", len(sectionRules), func(i int) {
+		sec, rule := sectionRules[i].section, sectionRules[i].rule
+		stats, err := c.firewallClient.GetFirewallStats(ctx, sec.Id, rule.Id)
+		if err != nil {
+			mtx.Lock()
+			errs = append(errs, fmt.Sprintf("unable to get firewall statistic of rule %s in section %s: %s", rule.Id, sec.Id, err))
+			mtx.Unlock()
+			return
+		}
+		firewallStatisticMetric := firewallStatisticMetric{
+			SectionID:    sec.Id,
+			RuleID:       rule.Id,
+			RuleName:     rule.DisplayName,
+			TotalPackets: float64(stats.PacketCount),
+			TotalBytes:   float64(stats.ByteCount),
 		}
+		mtx.Lock()
+		firewallStatisticMetrics = append(firewallStatisticMetrics, firewallStatisticMetric)
+		mtx.Unlock()
+	})
+	if len(errs) > 0 {
+		err = fmt.Errorf("%s", strings.Join(errs, "; "))
 	}
 	return
 }
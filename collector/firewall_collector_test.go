@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -30,11 +31,11 @@ type mockFirewallResponse struct {
 	Error      error
 }
 
-func (c *mockFirewallClient) ListAllFirewallSections() ([]manager.FirewallSection, error) {
+func (c *mockFirewallClient) ListAllFirewallSections(ctx context.Context) ([]manager.FirewallSection, error) {
 	panic("unused function. Only used to satisfy FirewallClient interface")
 }
 
-func (c *mockFirewallClient) GetAllFirewallRules(sectionID string) ([]manager.FirewallRule, error) {
+func (c *mockFirewallClient) GetAllFirewallRules(ctx context.Context, sectionID string) ([]manager.FirewallRule, error) {
 	if c.firewallRuleListError != nil {
 		return nil, c.firewallRuleListError
 	}
@@ -54,7 +55,7 @@ func (c *mockFirewallClient) GetAllFirewallRules(sectionID string) ([]manager.Fi
 	return firewallRules, nil
 }
 
-func (c *mockFirewallClient) GetFirewallStats(sectionID string, ruleID string) (manager.FirewallStats, error) {
+func (c *mockFirewallClient) GetFirewallStats(ctx context.Context, sectionID string, ruleID string) (manager.FirewallStats, error) {
 	for _, res := range c.responses {
 		if res.Section.Id != sectionID {
 			continue
@@ -111,6 +112,7 @@ func TestFirewallCollector_GenerateFirewallStatisticMetrics(t *testing.T) {
 		firewallRuleListError error
 		firewallResponses     []mockFirewallResponse
 		expectedMetrics       []firewallStatisticMetric
+		expectedError         bool
 	}{
 		{
 			description: "Should return correct statistics metrics",
@@ -148,20 +150,42 @@ func TestFirewallCollector_GenerateFirewallStatisticMetrics(t *testing.T) {
 			expectedMetrics:   []firewallStatisticMetric{},
 		},
 		{
-			description:           "Should return empty metrics when error listing nat rules",
+			description:           "Should return error when error listing firewall rules",
 			firewallRuleListError: errors.New("error list firewall rules"),
-			firewallResponses:     []mockFirewallResponse{},
-			expectedMetrics:       []firewallStatisticMetric{},
+			firewallResponses: []mockFirewallResponse{
+				buildFirewallResponse("1", []string{"1"}, nil),
+			},
+			expectedMetrics: []firewallStatisticMetric{},
+			expectedError:   true,
+		},
+		{
+			description: "Should return remaining metrics and error when error getting firewall statistic",
+			firewallResponses: []mockFirewallResponse{
+				buildFirewallResponse("1", []string{"1"}, nil),
+				buildFirewallResponse("2", []string{"2"}, errors.New("error get firewall statistic")),
+			},
+			expectedMetrics: []firewallStatisticMetric{
+				{
+					SectionID:    fmt.Sprintf("%s-1", fakeFirewallSectionID),
+					RuleID:       fmt.Sprintf("%s-1", fakeFirewallRuleID),
+					RuleName:     fmt.Sprintf("%s-1", fakeFirewallRuleName),
+					TotalPackets: fakeFirewallRuleTotalPackets,
+					TotalBytes:   fakeFirewallRuleTotalBytes,
+				},
+			},
+			expectedError: true,
 		},
 	}
 	for _, tc := range testcases {
 		mockFirewallClient := &mockFirewallClient{
-			responses: tc.firewallResponses,
+			responses:             tc.firewallResponses,
+			firewallRuleListError: tc.firewallRuleListError,
 		}
 		logger := log.NewNopLogger()
 		firewallCollector := newFirewallCollector(mockFirewallClient, logger)
 		firewallSections := buildFirewallSections(tc.firewallResponses)
-		metrics := firewallCollector.generateFirewallStatisticMetrics(firewallSections)
+		metrics, err := firewallCollector.generateFirewallStatisticMetrics(context.Background(), firewallSections)
+		assert.Equal(t, tc.expectedError, err != nil, tc.description)
 		assert.ElementsMatch(t, tc.expectedMetrics, metrics, tc.description)
 	}
 }
@@ -0,0 +1,325 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"nsxt_exporter/client"
+	"nsxt_exporter/client/vpn"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	ipsecVPNSessionPossibleStatus    = [...]string{"UP", "DOWN", "DEGRADED"}
+	ipsecVPNIKESessionPossibleStatus = [...]string{"UP", "DOWN", "NEGOTIATING"}
+	ipsecVPNTunnelPossibleStatus     = [...]string{"UP", "DOWN"}
+)
+
+func init() {
+	registerCollector("ipsec_vpn", defaultDisabled, createIPSecVPNCollectorFactory)
+}
+
+type ipsecVPNCollector struct {
+	ipsecVPNClient client.IPSecVPNClient
+	logger         log.Logger
+
+	sessionStatus            *prometheus.Desc
+	sessionIKEStatus         *prometheus.Desc
+	sessionNegotiatedTunnels *prometheus.Desc
+	sessionFailedTunnels     *prometheus.Desc
+	tunnelStatus             *prometheus.Desc
+	tunnelRxByte             *prometheus.Desc
+	tunnelRxPacket           *prometheus.Desc
+	tunnelRxDroppedPacket    *prometheus.Desc
+	tunnelTxByte             *prometheus.Desc
+	tunnelTxPacket           *prometheus.Desc
+	tunnelTxDroppedPacket    *prometheus.Desc
+}
+
+type ipsecVPNSessionMetric struct {
+	ID                string
+	Name              string
+	PeerAddress       string
+	PeerID            string
+	StatusDetail      map[string]float64
+	IKEStatusDetail   map[string]float64
+	NegotiatedTunnels float64
+	FailedTunnels     float64
+}
+
+type ipsecVPNTunnelMetric struct {
+	SessionID       string
+	SessionName     string
+	PeerAddress     string
+	PeerID          string
+	LocalSubnet     string
+	PeerSubnet      string
+	StatusDetail    map[string]float64
+	RxByte          float64
+	RxPacket        float64
+	RxDroppedPacket float64
+	TxByte          float64
+	TxPacket        float64
+	TxDroppedPacket float64
+}
+
+func createIPSecVPNCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
+	nsxtClient := client.NewNSXTClient(apiClient, logger)
+	return newIPSecVPNCollector(nsxtClient, logger)
+}
+
+func newIPSecVPNCollector(ipsecVPNClient client.IPSecVPNClient, logger log.Logger) *ipsecVPNCollector {
+	sessionLabels := []string{"session_id", "session_name", "peer_address", "peer_id"}
+	tunnelLabels := append(sessionLabels, "local_subnet", "peer_subnet")
+	sessionStatus := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ipsec_vpn_session", "status"),
+		"Status of IPSec VPN session",
+		append(sessionLabels, "status"),
+		nil,
+	)
+	sessionIKEStatus := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ipsec_vpn_session", "ike_status"),
+		"Status of IKE session of IPSec VPN session",
+		append(sessionLabels, "status"),
+		nil,
+	)
+	sessionNegotiatedTunnels := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ipsec_vpn_session", "negotiated_tunnels"),
+		"Number of negotiated tunnels of IPSec VPN session",
+		sessionLabels,
+		nil,
+	)
+	sessionFailedTunnels := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ipsec_vpn_session", "failed_tunnels"),
+		"Number of failed tunnels of IPSec VPN session",
+		sessionLabels,
+		nil,
+	)
+	tunnelStatus := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ipsec_vpn_tunnel", "status"),
+		"Status of IPSec VPN tunnel",
+		append(tunnelLabels, "status"),
+		nil,
+	)
+	tunnelRxByte := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ipsec_vpn_tunnel", "rx_byte"),
+		"Total bytes received (rx) on IPSec VPN tunnel",
+		tunnelLabels,
+		nil,
+	)
+	tunnelRxPacket := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ipsec_vpn_tunnel", "rx_packet"),
+		"Total packets received (rx) on IPSec VPN tunnel",
+		tunnelLabels,
+		nil,
+	)
+	tunnelRxDroppedPacket := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ipsec_vpn_tunnel", "rx_dropped_packet"),
+		"Total receive (rx) packets dropped on IPSec VPN tunnel",
+		tunnelLabels,
+		nil,
+	)
+	tunnelTxByte := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ipsec_vpn_tunnel", "tx_byte"),
+		"Total bytes transmitted (tx) on IPSec VPN tunnel",
+		tunnelLabels,
+		nil,
+	)
+	tunnelTxPacket := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ipsec_vpn_tunnel", "tx_packet"),
+		"Total packets transmitted (tx) on IPSec VPN tunnel",
+		tunnelLabels,
+		nil,
+	)
+	tunnelTxDroppedPacket := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ipsec_vpn_tunnel", "tx_dropped_packet"),
+		"Total transmit (tx) packets dropped on IPSec VPN tunnel",
+		tunnelLabels,
+		nil,
+	)
+	return &ipsecVPNCollector{
+		ipsecVPNClient:           ipsecVPNClient,
+		logger:                   logger,
+		sessionStatus:            sessionStatus,
+		sessionIKEStatus:         sessionIKEStatus,
+		sessionNegotiatedTunnels: sessionNegotiatedTunnels,
+		sessionFailedTunnels:     sessionFailedTunnels,
+		tunnelStatus:             tunnelStatus,
+		tunnelRxByte:             tunnelRxByte,
+		tunnelRxPacket:           tunnelRxPacket,
+		tunnelRxDroppedPacket:    tunnelRxDroppedPacket,
+		tunnelTxByte:             tunnelTxByte,
+		tunnelTxPacket:           tunnelTxPacket,
+		tunnelTxDroppedPacket:    tunnelTxDroppedPacket,
+	}
+}
+
+// Describe implements the Collector interface.
+func (c *ipsecVPNCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.sessionStatus
+	ch <- c.sessionIKEStatus
+	ch <- c.sessionNegotiatedTunnels
+	ch <- c.sessionFailedTunnels
+	ch <- c.tunnelStatus
+	ch <- c.tunnelRxByte
+	ch <- c.tunnelRxPacket
+	ch <- c.tunnelRxDroppedPacket
+	ch <- c.tunnelTxByte
+	ch <- c.tunnelTxPacket
+	ch <- c.tunnelTxDroppedPacket
+}
+
+// Update implements the Collector interface.
+func (c *ipsecVPNCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	sessions, err := c.ipsecVPNClient.ListAllIPSecVPNSessions(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list IPSec VPN sessions: %s", err)
+	}
+	peerEndpoints, err := c.ipsecVPNClient.ListAllIPSecVPNPeerEndpoints(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list IPSec VPN peer endpoints: %s", err)
+	}
+	peerEndpointsByID := make(map[string]vpn.IPSecVPNPeerEndpoint)
+	for _, peerEndpoint := range peerEndpoints {
+		peerEndpointsByID[peerEndpoint.Id] = peerEndpoint
+	}
+	sessionMetrics := c.generateIPSecVPNSessionMetrics(ctx, sessions, peerEndpointsByID)
+	for _, m := range sessionMetrics {
+		labels := []string{m.ID, m.Name, m.PeerAddress, m.PeerID}
+		for status, value := range m.StatusDetail {
+			ch <- prometheus.MustNewConstMetric(c.sessionStatus, prometheus.GaugeValue, value, append(labels, status)...)
+		}
+		for status, value := range m.IKEStatusDetail {
+			ch <- prometheus.MustNewConstMetric(c.sessionIKEStatus, prometheus.GaugeValue, value, append(labels, status)...)
+		}
+		ch <- prometheus.MustNewConstMetric(c.sessionNegotiatedTunnels, prometheus.GaugeValue, m.NegotiatedTunnels, labels...)
+		ch <- prometheus.MustNewConstMetric(c.sessionFailedTunnels, prometheus.GaugeValue, m.FailedTunnels, labels...)
+	}
+	tunnelMetrics := c.generateIPSecVPNTunnelMetrics(ctx, sessions, peerEndpointsByID)
+	for _, m := range tunnelMetrics {
+		labels := []string{m.SessionID, m.SessionName, m.PeerAddress, m.PeerID, m.LocalSubnet, m.PeerSubnet}
+		for status, value := range m.StatusDetail {
+			ch <- prometheus.MustNewConstMetric(c.tunnelStatus, prometheus.GaugeValue, value, append(labels, status)...)
+		}
+		ch <- prometheus.MustNewConstMetric(c.tunnelRxByte, prometheus.GaugeValue, m.RxByte, labels...)
+		ch <- prometheus.MustNewConstMetric(c.tunnelRxPacket, prometheus.GaugeValue, m.RxPacket, labels...)
+		ch <- prometheus.MustNewConstMetric(c.tunnelRxDroppedPacket, prometheus.GaugeValue, m.RxDroppedPacket, labels...)
+		ch <- prometheus.MustNewConstMetric(c.tunnelTxByte, prometheus.GaugeValue, m.TxByte, labels...)
+		ch <- prometheus.MustNewConstMetric(c.tunnelTxPacket, prometheus.GaugeValue, m.TxPacket, labels...)
+		ch <- prometheus.MustNewConstMetric(c.tunnelTxDroppedPacket, prometheus.GaugeValue, m.TxDroppedPacket, labels...)
+	}
+	return nil
+}
+
+func (c *ipsecVPNCollector) generateIPSecVPNSessionMetrics(ctx context.Context, sessions []vpn.IPSecVPNSession, peerEndpoints map[string]vpn.IPSecVPNPeerEndpoint) (sessionMetrics []ipsecVPNSessionMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "ipsec_vpn", len(sessions), func(i int) {
+		session := sessions[i]
+		sessionStatus, err := c.ipsecVPNClient.GetIPSecVPNSessionStatus(ctx, session.Id)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "Unable to get IPSec VPN session status", "id", session.Id, "err", err)
+			return
+		}
+		peerEndpoint := peerEndpoints[session.PeerEndpointId]
+		var ikeStatus string
+		if sessionStatus.IkeStatus != nil {
+			ikeStatus = sessionStatus.IkeStatus.IkeSessionState
+		}
+		sessionMetric := ipsecVPNSessionMetric{
+			ID:                session.Id,
+			Name:              session.DisplayName,
+			PeerAddress:       peerEndpoint.PeerAddress,
+			PeerID:            peerEndpoint.PeerId,
+			StatusDetail:      vpnStatusDetail(ipsecVPNSessionPossibleStatus[:], sessionStatus.SessionStatus),
+			IKEStatusDetail:   vpnStatusDetail(ipsecVPNIKESessionPossibleStatus[:], ikeStatus),
+			NegotiatedTunnels: float64(sessionStatus.NegotiatedTunnels),
+			FailedTunnels:     float64(sessionStatus.FailedTunnels),
+		}
+		mtx.Lock()
+		sessionMetrics = append(sessionMetrics, sessionMetric)
+		mtx.Unlock()
+	})
+	return
+}
+
+// generateIPSecVPNTunnelMetrics returns the status and traffic of the tunnels
+// of every IPSec VPN session. Policy based sessions have a tunnel per pair of
+// local and peer subnet of their policy rules.
+func (c *ipsecVPNCollector) generateIPSecVPNTunnelMetrics(ctx context.Context, sessions []vpn.IPSecVPNSession, peerEndpoints map[string]vpn.IPSecVPNPeerEndpoint) (tunnelMetrics []ipsecVPNTunnelMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "ipsec_vpn", len(sessions), func(i int) {
+		session := sessions[i]
+		sessionStatistics, err := c.ipsecVPNClient.GetIPSecVPNSessionStatistics(ctx, session.Id)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "Unable to get IPSec VPN session statistics", "id", session.Id, "err", err)
+			return
+		}
+		peerEndpoint := peerEndpoints[session.PeerEndpointId]
+		var sessionTunnelMetrics []ipsecVPNTunnelMetric
+		for _, policyStatistics := range sessionStatistics.PolicyStatistics {
+			for _, tunnelStatistics := range policyStatistics.TunnelStatistics {
+				sessionTunnelMetrics = append(sessionTunnelMetrics, ipsecVPNTunnelMetric{
+					SessionID:       session.Id,
+					SessionName:     session.DisplayName,
+					PeerAddress:     peerEndpoint.PeerAddress,
+					PeerID:          peerEndpoint.PeerId,
+					LocalSubnet:     tunnelStatistics.LocalSubnet,
+					PeerSubnet:      tunnelStatistics.PeerSubnet,
+					StatusDetail:    vpnStatusDetail(ipsecVPNTunnelPossibleStatus[:], tunnelStatistics.TunnelStatus),
+					RxByte:          float64(tunnelStatistics.BytesIn),
+					RxPacket:        float64(tunnelStatistics.PacketsIn),
+					RxDroppedPacket: float64(tunnelStatistics.DroppedPacketsIn),
+					TxByte:          float64(tunnelStatistics.BytesOut),
+					TxPacket:        float64(tunnelStatistics.PacketsOut),
+					TxDroppedPacket: float64(tunnelStatistics.DroppedPacketsOut),
+				})
+			}
+		}
+		// Route-based sessions have a single tunnel without policies, whose
+		// traffic is only given by the counters of the session. Its status
+		// is the status of the session.
+		if len(sessionStatistics.PolicyStatistics) == 0 && sessionStatistics.AggregateTrafficCounters != nil {
+			var statusDetail map[string]float64
+			sessionStatus, err := c.ipsecVPNClient.GetIPSecVPNSessionStatus(ctx, session.Id)
+			if err != nil {
+				level.Error(c.logger).Log("msg", "Unable to get IPSec VPN session status", "id", session.Id, "err", err)
+			} else {
+				statusDetail = vpnStatusDetail(ipsecVPNTunnelPossibleStatus[:], sessionStatus.SessionStatus)
+			}
+			counters := sessionStatistics.AggregateTrafficCounters
+			sessionTunnelMetrics = append(sessionTunnelMetrics, ipsecVPNTunnelMetric{
+				SessionID:       session.Id,
+				SessionName:     session.DisplayName,
+				PeerAddress:     peerEndpoint.PeerAddress,
+				PeerID:          peerEndpoint.PeerId,
+				StatusDetail:    statusDetail,
+				RxByte:          float64(counters.BytesIn),
+				RxPacket:        float64(counters.PacketsIn),
+				RxDroppedPacket: float64(counters.DroppedPacketsIn),
+				TxByte:          float64(counters.BytesOut),
+				TxPacket:        float64(counters.PacketsOut),
+				TxDroppedPacket: float64(counters.DroppedPacketsOut),
+			})
+		}
+		mtx.Lock()
+		tunnelMetrics = append(tunnelMetrics, sessionTunnelMetrics...)
+		mtx.Unlock()
+	})
+	return
+}
+
+func vpnStatusDetail(possibleStatus []string, status string) map[string]float64 {
+	statusDetail := make(map[string]float64)
+	for _, s := range possibleStatus {
+		statusValue := 0.0
+		if s == strings.ToUpper(status) {
+			statusValue = 1.0
+		}
+		statusDetail[s] = statusValue
+	}
+	return statusDetail
+}
@@ -0,0 +1,323 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"nsxt_exporter/client/vpn"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	fakeIPSecVPNSessionID      = "fake-session-id"
+	fakeIPSecVPNOtherSessionID = "fake-other-session-id"
+	fakeIPSecVPNPeerEndpointID = "fake-peer-endpoint-id"
+	fakeIPSecVPNPeerAddress    = "203.0.113.1"
+	fakeIPSecVPNPeerID         = "fake-peer-id"
+	fakeIPSecVPNLocalSubnet    = "10.0.0.0/24"
+	fakeIPSecVPNPeerSubnet     = "10.1.0.0/24"
+)
+
+type mockIPSecVPNClient struct {
+	statusResponses     map[string]mockIPSecVPNStatusResponse
+	statisticsResponses map[string]mockIPSecVPNStatisticsResponse
+}
+
+type mockIPSecVPNStatusResponse struct {
+	SessionStatus vpn.IPSecVPNSessionStatus
+	Error         error
+}
+
+type mockIPSecVPNStatisticsResponse struct {
+	SessionStatistics vpn.IPSecVPNSessionStatistics
+	Error             error
+}
+
+func (c *mockIPSecVPNClient) ListAllIPSecVPNSessions(ctx context.Context) ([]vpn.IPSecVPNSession, error) {
+	panic("unused function. Only used to satisfy IPSecVPNClient interface")
+}
+
+func (c *mockIPSecVPNClient) ListAllIPSecVPNPeerEndpoints(ctx context.Context) ([]vpn.IPSecVPNPeerEndpoint, error) {
+	panic("unused function. Only used to satisfy IPSecVPNClient interface")
+}
+
+func (c *mockIPSecVPNClient) GetIPSecVPNSessionStatus(ctx context.Context, sessionID string) (vpn.IPSecVPNSessionStatus, error) {
+	res, ok := c.statusResponses[sessionID]
+	if !ok {
+		return vpn.IPSecVPNSessionStatus{}, errors.New("error session not found")
+	}
+	return res.SessionStatus, res.Error
+}
+
+func (c *mockIPSecVPNClient) GetIPSecVPNSessionStatistics(ctx context.Context, sessionID string) (vpn.IPSecVPNSessionStatistics, error) {
+	res, ok := c.statisticsResponses[sessionID]
+	if !ok {
+		return vpn.IPSecVPNSessionStatistics{}, errors.New("error session not found")
+	}
+	return res.SessionStatistics, res.Error
+}
+
+func buildIPSecVPNSessions() []vpn.IPSecVPNSession {
+	return []vpn.IPSecVPNSession{
+		{Id: fakeIPSecVPNSessionID, DisplayName: fakeIPSecVPNSessionID + "-name", PeerEndpointId: fakeIPSecVPNPeerEndpointID},
+		{Id: fakeIPSecVPNOtherSessionID, DisplayName: fakeIPSecVPNOtherSessionID + "-name", PeerEndpointId: "fake-unknown-peer-endpoint-id"},
+	}
+}
+
+func buildIPSecVPNPeerEndpoints() map[string]vpn.IPSecVPNPeerEndpoint {
+	return map[string]vpn.IPSecVPNPeerEndpoint{
+		fakeIPSecVPNPeerEndpointID: {Id: fakeIPSecVPNPeerEndpointID, PeerAddress: fakeIPSecVPNPeerAddress, PeerId: fakeIPSecVPNPeerID},
+	}
+}
+
+func buildExpectedVPNStatusDetail(possibleStatus []string, status string) map[string]float64 {
+	statusDetail := make(map[string]float64)
+	for _, s := range possibleStatus {
+		statusDetail[s] = 0.0
+	}
+	if status != "" {
+		statusDetail[status] = 1.0
+	}
+	return statusDetail
+}
+
+func TestIPSecVPNCollector_GenerateIPSecVPNSessionMetrics(t *testing.T) {
+	testcases := []struct {
+		description     string
+		responses       map[string]mockIPSecVPNStatusResponse
+		expectedMetrics []ipsecVPNSessionMetric
+	}{
+		{
+			description: "Should return session metrics of every session",
+			responses: map[string]mockIPSecVPNStatusResponse{
+				fakeIPSecVPNSessionID: {SessionStatus: vpn.IPSecVPNSessionStatus{
+					SessionStatus:     "UP",
+					IkeStatus:         &vpn.IPSecVPNIKESessionStatus{IkeSessionState: "UP"},
+					NegotiatedTunnels: 2,
+				}},
+				fakeIPSecVPNOtherSessionID: {SessionStatus: vpn.IPSecVPNSessionStatus{
+					SessionStatus: "degraded",
+					IkeStatus:     &vpn.IPSecVPNIKESessionStatus{IkeSessionState: "negotiating"},
+					FailedTunnels: 1,
+				}},
+			},
+			expectedMetrics: []ipsecVPNSessionMetric{
+				{
+					ID:                fakeIPSecVPNSessionID,
+					Name:              fakeIPSecVPNSessionID + "-name",
+					PeerAddress:       fakeIPSecVPNPeerAddress,
+					PeerID:            fakeIPSecVPNPeerID,
+					StatusDetail:      buildExpectedVPNStatusDetail(ipsecVPNSessionPossibleStatus[:], "UP"),
+					IKEStatusDetail:   buildExpectedVPNStatusDetail(ipsecVPNIKESessionPossibleStatus[:], "UP"),
+					NegotiatedTunnels: 2,
+				},
+				{
+					ID:              fakeIPSecVPNOtherSessionID,
+					Name:            fakeIPSecVPNOtherSessionID + "-name",
+					StatusDetail:    buildExpectedVPNStatusDetail(ipsecVPNSessionPossibleStatus[:], "DEGRADED"),
+					IKEStatusDetail: buildExpectedVPNStatusDetail(ipsecVPNIKESessionPossibleStatus[:], "NEGOTIATING"),
+					FailedTunnels:   1,
+				},
+			},
+		},
+		{
+			description: "Should only return session metrics with valid response",
+			responses: map[string]mockIPSecVPNStatusResponse{
+				fakeIPSecVPNSessionID:      {SessionStatus: vpn.IPSecVPNSessionStatus{SessionStatus: "DOWN"}},
+				fakeIPSecVPNOtherSessionID: {Error: errors.New("error get session status")},
+			},
+			expectedMetrics: []ipsecVPNSessionMetric{
+				{
+					ID:              fakeIPSecVPNSessionID,
+					Name:            fakeIPSecVPNSessionID + "-name",
+					PeerAddress:     fakeIPSecVPNPeerAddress,
+					PeerID:          fakeIPSecVPNPeerID,
+					StatusDetail:    buildExpectedVPNStatusDetail(ipsecVPNSessionPossibleStatus[:], "DOWN"),
+					IKEStatusDetail: buildExpectedVPNStatusDetail(ipsecVPNIKESessionPossibleStatus[:], ""),
+				},
+			},
+		},
+		{
+			description:     "Should return empty metrics when empty response",
+			responses:       map[string]mockIPSecVPNStatusResponse{},
+			expectedMetrics: []ipsecVPNSessionMetric{},
+		},
+	}
+	for _, tc := range testcases {
+		mockIPSecVPNClient := &mockIPSecVPNClient{
+			statusResponses: tc.responses,
+		}
+		ipsecVPNCollector := newIPSecVPNCollector(mockIPSecVPNClient, log.NewNopLogger())
+		sessionMetrics := ipsecVPNCollector.generateIPSecVPNSessionMetrics(context.Background(), buildIPSecVPNSessions(), buildIPSecVPNPeerEndpoints())
+		assert.ElementsMatch(t, tc.expectedMetrics, sessionMetrics, tc.description)
+	}
+}
+
+func TestIPSecVPNCollector_GenerateIPSecVPNTunnelMetrics(t *testing.T) {
+	testcases := []struct {
+		description     string
+		responses       map[string]mockIPSecVPNStatisticsResponse
+		statusResponses map[string]mockIPSecVPNStatusResponse
+		expectedMetrics []ipsecVPNTunnelMetric
+	}{
+		{
+			description: "Should return tunnel metrics of every policy of session",
+			responses: map[string]mockIPSecVPNStatisticsResponse{
+				fakeIPSecVPNSessionID: {SessionStatistics: vpn.IPSecVPNSessionStatistics{
+					PolicyStatistics: []vpn.IPSecVPNPolicyTrafficStatistics{
+						{TunnelStatistics: []vpn.IPSecVPNTunnelTrafficStatistics{
+							{
+								LocalSubnet:       fakeIPSecVPNLocalSubnet,
+								PeerSubnet:        fakeIPSecVPNPeerSubnet,
+								TunnelStatus:      "UP",
+								BytesIn:           1,
+								PacketsIn:         2,
+								DroppedPacketsIn:  3,
+								BytesOut:          4,
+								PacketsOut:        5,
+								DroppedPacketsOut: 6,
+							},
+						}},
+						{TunnelStatistics: []vpn.IPSecVPNTunnelTrafficStatistics{
+							{LocalSubnet: fakeIPSecVPNLocalSubnet, PeerSubnet: "10.2.0.0/24", TunnelStatus: "down"},
+						}},
+					},
+				}},
+				fakeIPSecVPNOtherSessionID: {SessionStatistics: vpn.IPSecVPNSessionStatistics{}},
+			},
+			expectedMetrics: []ipsecVPNTunnelMetric{
+				{
+					SessionID:       fakeIPSecVPNSessionID,
+					SessionName:     fakeIPSecVPNSessionID + "-name",
+					PeerAddress:     fakeIPSecVPNPeerAddress,
+					PeerID:          fakeIPSecVPNPeerID,
+					LocalSubnet:     fakeIPSecVPNLocalSubnet,
+					PeerSubnet:      fakeIPSecVPNPeerSubnet,
+					StatusDetail:    buildExpectedVPNStatusDetail(ipsecVPNTunnelPossibleStatus[:], "UP"),
+					RxByte:          1,
+					RxPacket:        2,
+					RxDroppedPacket: 3,
+					TxByte:          4,
+					TxPacket:        5,
+					TxDroppedPacket: 6,
+				},
+				{
+					SessionID:    fakeIPSecVPNSessionID,
+					SessionName:  fakeIPSecVPNSessionID + "-name",
+					PeerAddress:  fakeIPSecVPNPeerAddress,
+					PeerID:       fakeIPSecVPNPeerID,
+					LocalSubnet:  fakeIPSecVPNLocalSubnet,
+					PeerSubnet:   "10.2.0.0/24",
+					StatusDetail: buildExpectedVPNStatusDetail(ipsecVPNTunnelPossibleStatus[:], "DOWN"),
+				},
+			},
+		},
+		{
+			description: "Should return session status and traffic of route-based session without policies",
+			statusResponses: map[string]mockIPSecVPNStatusResponse{
+				fakeIPSecVPNSessionID: {SessionStatus: vpn.IPSecVPNSessionStatus{SessionStatus: "UP"}},
+			},
+			responses: map[string]mockIPSecVPNStatisticsResponse{
+				fakeIPSecVPNSessionID: {SessionStatistics: vpn.IPSecVPNSessionStatistics{
+					AggregateTrafficCounters: &vpn.IPSecVPNTrafficCounters{
+						BytesIn:           1,
+						PacketsIn:         2,
+						DroppedPacketsIn:  3,
+						BytesOut:          4,
+						PacketsOut:        5,
+						DroppedPacketsOut: 6,
+					},
+				}},
+				fakeIPSecVPNOtherSessionID: {SessionStatistics: vpn.IPSecVPNSessionStatistics{
+					AggregateTrafficCounters: &vpn.IPSecVPNTrafficCounters{BytesIn: 10},
+					PolicyStatistics: []vpn.IPSecVPNPolicyTrafficStatistics{
+						{TunnelStatistics: []vpn.IPSecVPNTunnelTrafficStatistics{
+							{LocalSubnet: fakeIPSecVPNLocalSubnet, PeerSubnet: fakeIPSecVPNPeerSubnet, TunnelStatus: "UP", BytesIn: 10},
+						}},
+					},
+				}},
+			},
+			expectedMetrics: []ipsecVPNTunnelMetric{
+				{
+					SessionID:       fakeIPSecVPNSessionID,
+					SessionName:     fakeIPSecVPNSessionID + "-name",
+					PeerAddress:     fakeIPSecVPNPeerAddress,
+					PeerID:          fakeIPSecVPNPeerID,
+					StatusDetail:    buildExpectedVPNStatusDetail(ipsecVPNTunnelPossibleStatus[:], "UP"),
+					RxByte:          1,
+					RxPacket:        2,
+					RxDroppedPacket: 3,
+					TxByte:          4,
+					TxPacket:        5,
+					TxDroppedPacket: 6,
+				},
+				{
+					SessionID:    fakeIPSecVPNOtherSessionID,
+					SessionName:  fakeIPSecVPNOtherSessionID + "-name",
+					LocalSubnet:  fakeIPSecVPNLocalSubnet,
+					PeerSubnet:   fakeIPSecVPNPeerSubnet,
+					StatusDetail: buildExpectedVPNStatusDetail(ipsecVPNTunnelPossibleStatus[:], "UP"),
+					RxByte:       10,
+				},
+			},
+		},
+		{
+			description: "Should return traffic of route-based session when session status cannot be retrieved",
+			responses: map[string]mockIPSecVPNStatisticsResponse{
+				fakeIPSecVPNSessionID: {SessionStatistics: vpn.IPSecVPNSessionStatistics{
+					AggregateTrafficCounters: &vpn.IPSecVPNTrafficCounters{BytesIn: 1},
+				}},
+			},
+			statusResponses: map[string]mockIPSecVPNStatusResponse{
+				fakeIPSecVPNSessionID: {Error: errors.New("error get session status")},
+			},
+			expectedMetrics: []ipsecVPNTunnelMetric{
+				{
+					SessionID:   fakeIPSecVPNSessionID,
+					SessionName: fakeIPSecVPNSessionID + "-name",
+					PeerAddress: fakeIPSecVPNPeerAddress,
+					PeerID:      fakeIPSecVPNPeerID,
+					RxByte:      1,
+				},
+			},
+		},
+		{
+			description: "Should only return tunnel metrics with valid response",
+			responses: map[string]mockIPSecVPNStatisticsResponse{
+				fakeIPSecVPNSessionID: {Error: errors.New("error get session statistics")},
+				fakeIPSecVPNOtherSessionID: {SessionStatistics: vpn.IPSecVPNSessionStatistics{
+					PolicyStatistics: []vpn.IPSecVPNPolicyTrafficStatistics{
+						{TunnelStatistics: []vpn.IPSecVPNTunnelTrafficStatistics{
+							{LocalSubnet: fakeIPSecVPNLocalSubnet, PeerSubnet: fakeIPSecVPNPeerSubnet, TunnelStatus: "UP"},
+						}},
+					},
+				}},
+			},
+			expectedMetrics: []ipsecVPNTunnelMetric{
+				{
+					SessionID:    fakeIPSecVPNOtherSessionID,
+					SessionName:  fakeIPSecVPNOtherSessionID + "-name",
+					LocalSubnet:  fakeIPSecVPNLocalSubnet,
+					PeerSubnet:   fakeIPSecVPNPeerSubnet,
+					StatusDetail: buildExpectedVPNStatusDetail(ipsecVPNTunnelPossibleStatus[:], "UP"),
+				},
+			},
+		},
+		{
+			description:     "Should return empty metrics when empty response",
+			responses:       map[string]mockIPSecVPNStatisticsResponse{},
+			expectedMetrics: []ipsecVPNTunnelMetric{},
+		},
+	}
+	for _, tc := range testcases {
+		mockIPSecVPNClient := &mockIPSecVPNClient{
+			statusResponses:     tc.statusResponses,
+			statisticsResponses: tc.responses,
+		}
+		ipsecVPNCollector := newIPSecVPNCollector(mockIPSecVPNClient, log.NewNopLogger())
+		tunnelMetrics := ipsecVPNCollector.generateIPSecVPNTunnelMetrics(context.Background(), buildIPSecVPNSessions(), buildIPSecVPNPeerEndpoints())
+		assert.ElementsMatch(t, tc.expectedMetrics, tunnelMetrics, tc.description)
+	}
+}
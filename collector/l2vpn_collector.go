@@ -0,0 +1,281 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"nsxt_exporter/client"
+	"nsxt_exporter/client/vpn"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var l2vpnSessionPossibleStatus = [...]string{"UP", "DOWN"}
+
+func init() {
+	registerCollector("l2vpn", defaultDisabled, createL2VPNCollectorFactory)
+}
+
+type l2vpnCollector struct {
+	l2vpnClient client.L2VPNClient
+	logger      log.Logger
+
+	sessionStatus            *prometheus.Desc
+	tunnelStatus             *prometheus.Desc
+	logicalSwitchRxByte      *prometheus.Desc
+	logicalSwitchRxPacket    *prometheus.Desc
+	logicalSwitchRxBumByte   *prometheus.Desc
+	logicalSwitchRxBumPacket *prometheus.Desc
+	logicalSwitchTxByte      *prometheus.Desc
+	logicalSwitchTxPacket    *prometheus.Desc
+	logicalSwitchTxBumByte   *prometheus.Desc
+	logicalSwitchTxBumPacket *prometheus.Desc
+}
+
+type l2vpnSessionStatusMetric struct {
+	ID           string
+	Name         string
+	StatusDetail map[string]float64
+	Tunnels      []l2vpnTunnelStatusMetric
+}
+
+type l2vpnTunnelStatusMetric struct {
+	ID           string
+	Name         string
+	StatusDetail map[string]float64
+}
+
+type l2vpnLogicalSwitchStatisticMetric struct {
+	SessionID         string
+	SessionName       string
+	LogicalSwitchID   string
+	LogicalSwitchName string
+	RxByte            float64
+	RxPacket          float64
+	RxBumByte         float64
+	RxBumPacket       float64
+	TxByte            float64
+	TxPacket          float64
+	TxBumByte         float64
+	TxBumPacket       float64
+}
+
+func createL2VPNCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
+	nsxtClient := client.NewNSXTClient(apiClient, logger)
+	return newL2VPNCollector(nsxtClient, logger)
+}
+
+func newL2VPNCollector(l2vpnClient client.L2VPNClient, logger log.Logger) *l2vpnCollector {
+	logicalSwitchLabels := []string{"session_id", "session_name", "logical_switch_id", "logical_switch_name"}
+	sessionStatus := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "l2vpn_session", "status"),
+		"Status of L2VPN session",
+		[]string{"session_id", "session_name", "status"},
+		nil,
+	)
+	tunnelStatus := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "l2vpn_tunnel", "status"),
+		"Status of transport tunnel of L2VPN session",
+		[]string{"session_id", "session_name", "tunnel_id", "tunnel_name", "status"},
+		nil,
+	)
+	logicalSwitchRxByte := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "l2vpn_logical_switch", "rx_byte"),
+		"Total bytes received (rx) on logical switch stretched by L2VPN session",
+		logicalSwitchLabels,
+		nil,
+	)
+	logicalSwitchRxPacket := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "l2vpn_logical_switch", "rx_packet"),
+		"Total packets received (rx) on logical switch stretched by L2VPN session",
+		logicalSwitchLabels,
+		nil,
+	)
+	logicalSwitchRxBumByte := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "l2vpn_logical_switch", "rx_bum_byte"),
+		"Total broadcast, unknown unicast and multicast (BUM) bytes received (rx) on logical switch stretched by L2VPN session",
+		logicalSwitchLabels,
+		nil,
+	)
+	logicalSwitchRxBumPacket := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "l2vpn_logical_switch", "rx_bum_packet"),
+		"Total broadcast, unknown unicast and multicast (BUM) packets received (rx) on logical switch stretched by L2VPN session",
+		logicalSwitchLabels,
+		nil,
+	)
+	logicalSwitchTxByte := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "l2vpn_logical_switch", "tx_byte"),
+		"Total bytes transmitted (tx) on logical switch stretched by L2VPN session",
+		logicalSwitchLabels,
+		nil,
+	)
+	logicalSwitchTxPacket := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "l2vpn_logical_switch", "tx_packet"),
+		"Total packets transmitted (tx) on logical switch stretched by L2VPN session",
+		logicalSwitchLabels,
+		nil,
+	)
+	logicalSwitchTxBumByte := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "l2vpn_logical_switch", "tx_bum_byte"),
+		"Total broadcast, unknown unicast and multicast (BUM) bytes transmitted (tx) on logical switch stretched by L2VPN session",
+		logicalSwitchLabels,
+		nil,
+	)
+	logicalSwitchTxBumPacket := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "l2vpn_logical_switch", "tx_bum_packet"),
+		"Total broadcast, unknown unicast and multicast (BUM) packets transmitted (tx) on logical switch stretched by L2VPN session",
+		logicalSwitchLabels,
+		nil,
+	)
+	return &l2vpnCollector{
+		l2vpnClient:              l2vpnClient,
+		logger:                   logger,
+		sessionStatus:            sessionStatus,
+		tunnelStatus:             tunnelStatus,
+		logicalSwitchRxByte:      logicalSwitchRxByte,
+		logicalSwitchRxPacket:    logicalSwitchRxPacket,
+		logicalSwitchRxBumByte:   logicalSwitchRxBumByte,
+		logicalSwitchRxBumPacket: logicalSwitchRxBumPacket,
+		logicalSwitchTxByte:      logicalSwitchTxByte,
+		logicalSwitchTxPacket:    logicalSwitchTxPacket,
+		logicalSwitchTxBumByte:   logicalSwitchTxBumByte,
+		logicalSwitchTxBumPacket: logicalSwitchTxBumPacket,
+	}
+}
+
+// Describe implements the Collector interface.
+func (c *l2vpnCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.sessionStatus
+	ch <- c.tunnelStatus
+	ch <- c.logicalSwitchRxByte
+	ch <- c.logicalSwitchRxPacket
+	ch <- c.logicalSwitchRxBumByte
+	ch <- c.logicalSwitchRxBumPacket
+	ch <- c.logicalSwitchTxByte
+	ch <- c.logicalSwitchTxPacket
+	ch <- c.logicalSwitchTxBumByte
+	ch <- c.logicalSwitchTxBumPacket
+}
+
+// Update implements the Collector interface.
+func (c *l2vpnCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	sessions, err := c.l2vpnClient.ListAllL2VPNSessions(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list L2VPN sessions: %s", err)
+	}
+	logicalSwitches, err := c.l2vpnClient.ListAllLogicalSwitches(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list logical switches: %s", err)
+	}
+	logicalSwitchNames := make(map[string]string)
+	for _, logicalSwitch := range logicalSwitches {
+		logicalSwitchNames[logicalSwitch.Id] = logicalSwitch.DisplayName
+	}
+	sessionStatusMetrics := c.generateL2VPNSessionStatusMetrics(ctx, sessions)
+	for _, m := range sessionStatusMetrics {
+		for status, value := range m.StatusDetail {
+			ch <- prometheus.MustNewConstMetric(c.sessionStatus, prometheus.GaugeValue, value, m.ID, m.Name, status)
+		}
+		for _, tunnel := range m.Tunnels {
+			for status, value := range tunnel.StatusDetail {
+				ch <- prometheus.MustNewConstMetric(c.tunnelStatus, prometheus.GaugeValue, value, m.ID, m.Name, tunnel.ID, tunnel.Name, status)
+			}
+		}
+	}
+	logicalSwitchStatisticMetrics := c.generateL2VPNLogicalSwitchStatisticMetrics(ctx, sessions, logicalSwitchNames)
+	for _, m := range logicalSwitchStatisticMetrics {
+		labels := []string{m.SessionID, m.SessionName, m.LogicalSwitchID, m.LogicalSwitchName}
+		ch <- prometheus.MustNewConstMetric(c.logicalSwitchRxByte, prometheus.GaugeValue, m.RxByte, labels...)
+		ch <- prometheus.MustNewConstMetric(c.logicalSwitchRxPacket, prometheus.GaugeValue, m.RxPacket, labels...)
+		ch <- prometheus.MustNewConstMetric(c.logicalSwitchRxBumByte, prometheus.GaugeValue, m.RxBumByte, labels...)
+		ch <- prometheus.MustNewConstMetric(c.logicalSwitchRxBumPacket, prometheus.GaugeValue, m.RxBumPacket, labels...)
+		ch <- prometheus.MustNewConstMetric(c.logicalSwitchTxByte, prometheus.GaugeValue, m.TxByte, labels...)
+		ch <- prometheus.MustNewConstMetric(c.logicalSwitchTxPacket, prometheus.GaugeValue, m.TxPacket, labels...)
+		ch <- prometheus.MustNewConstMetric(c.logicalSwitchTxBumByte, prometheus.GaugeValue, m.TxBumByte, labels...)
+		ch <- prometheus.MustNewConstMetric(c.logicalSwitchTxBumPacket, prometheus.GaugeValue, m.TxBumPacket, labels...)
+	}
+	return nil
+}
+
+// generateL2VPNSessionStatusMetrics returns the status of every L2VPN session
+// and of its transport tunnels. The status of an IPSec transport tunnel is the
+// status of its IPSec VPN session.
+func (c *l2vpnCollector) generateL2VPNSessionStatusMetrics(ctx context.Context, sessions []vpn.L2VPNSession) (sessionStatusMetrics []l2vpnSessionStatusMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "l2vpn", len(sessions), func(i int) {
+		session := sessions[i]
+		sessionStatus, err := c.l2vpnClient.GetL2VPNSessionStatus(ctx, session.Id)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "Unable to get L2VPN session status", "id", session.Id, "err", err)
+			return
+		}
+		sessionStatusMetric := l2vpnSessionStatusMetric{
+			ID:           session.Id,
+			Name:         session.DisplayName,
+			StatusDetail: vpnStatusDetail(l2vpnSessionPossibleStatus[:], sessionStatus.Status),
+		}
+		for _, tunnelStatus := range sessionStatus.TransportTunnels {
+			if tunnelStatus.TunnelId == nil {
+				continue
+			}
+			var status string
+			if tunnelStatus.SessionStatus != nil {
+				status = tunnelStatus.SessionStatus.SessionStatus
+			}
+			sessionStatusMetric.Tunnels = append(sessionStatusMetric.Tunnels, l2vpnTunnelStatusMetric{
+				ID:           tunnelStatus.TunnelId.TargetId,
+				Name:         tunnelStatus.TunnelId.TargetDisplayName,
+				StatusDetail: vpnStatusDetail(ipsecVPNSessionPossibleStatus[:], status),
+			})
+		}
+		mtx.Lock()
+		sessionStatusMetrics = append(sessionStatusMetrics, sessionStatusMetric)
+		mtx.Unlock()
+	})
+	return
+}
+
+// generateL2VPNLogicalSwitchStatisticMetrics returns the traffic of every
+// logical switch stretched by an L2VPN session. Logical switches are named by
+// logicalSwitchNames, or by the name in the statistics if they are missing.
+func (c *l2vpnCollector) generateL2VPNLogicalSwitchStatisticMetrics(ctx context.Context, sessions []vpn.L2VPNSession, logicalSwitchNames map[string]string) (logicalSwitchStatisticMetrics []l2vpnLogicalSwitchStatisticMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "l2vpn", len(sessions), func(i int) {
+		session := sessions[i]
+		sessionStatistics, err := c.l2vpnClient.GetL2VPNSessionStatistics(ctx, session.Id)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "Unable to get L2VPN session statistics", "id", session.Id, "err", err)
+			return
+		}
+		var sessionMetrics []l2vpnLogicalSwitchStatisticMetric
+		for _, statistics := range sessionStatistics.TrafficStatisticsPerLogicalSwitch {
+			if statistics.LogicalSwitch == nil {
+				continue
+			}
+			logicalSwitchName, ok := logicalSwitchNames[statistics.LogicalSwitch.TargetId]
+			if !ok {
+				logicalSwitchName = statistics.LogicalSwitch.TargetDisplayName
+			}
+			sessionMetrics = append(sessionMetrics, l2vpnLogicalSwitchStatisticMetric{
+				SessionID:         session.Id,
+				SessionName:       session.DisplayName,
+				LogicalSwitchID:   statistics.LogicalSwitch.TargetId,
+				LogicalSwitchName: logicalSwitchName,
+				RxByte:            float64(statistics.BytesIn),
+				RxPacket:          float64(statistics.PacketsIn),
+				RxBumByte:         float64(statistics.BumBytesIn),
+				RxBumPacket:       float64(statistics.BumPacketsIn),
+				TxByte:            float64(statistics.BytesOut),
+				TxPacket:          float64(statistics.PacketsOut),
+				TxBumByte:         float64(statistics.BumBytesOut),
+				TxBumPacket:       float64(statistics.BumPacketsOut),
+			})
+		}
+		mtx.Lock()
+		logicalSwitchStatisticMetrics = append(logicalSwitchStatisticMetrics, sessionMetrics...)
+		mtx.Unlock()
+	})
+	return
+}
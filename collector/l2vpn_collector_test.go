@@ -0,0 +1,246 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"nsxt_exporter/client/vpn"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/go-vmware-nsxt/common"
+	"github.com/vmware/go-vmware-nsxt/manager"
+)
+
+const (
+	fakeL2VPNSessionID         = "fake-session-id"
+	fakeL2VPNOtherSessionID    = "fake-other-session-id"
+	fakeL2VPNTunnelID          = "fake-tunnel-id"
+	fakeL2VPNLogicalSwitchID   = "fake-logical-switch-id"
+	fakeL2VPNLogicalSwitchName = "fake-logical-switch-name"
+)
+
+type mockL2VPNClient struct {
+	statusResponses     map[string]mockL2VPNStatusResponse
+	statisticsResponses map[string]mockL2VPNStatisticsResponse
+}
+
+type mockL2VPNStatusResponse struct {
+	SessionStatus vpn.L2VPNSessionStatus
+	Error         error
+}
+
+type mockL2VPNStatisticsResponse struct {
+	SessionStatistics vpn.L2VPNSessionStatistics
+	Error             error
+}
+
+func (c *mockL2VPNClient) ListAllL2VPNSessions(ctx context.Context) ([]vpn.L2VPNSession, error) {
+	panic("unused function. Only used to satisfy L2VPNClient interface")
+}
+
+func (c *mockL2VPNClient) GetL2VPNSessionStatus(ctx context.Context, sessionID string) (vpn.L2VPNSessionStatus, error) {
+	res, ok := c.statusResponses[sessionID]
+	if !ok {
+		return vpn.L2VPNSessionStatus{}, errors.New("error session not found")
+	}
+	return res.SessionStatus, res.Error
+}
+
+func (c *mockL2VPNClient) GetL2VPNSessionStatistics(ctx context.Context, sessionID string) (vpn.L2VPNSessionStatistics, error) {
+	res, ok := c.statisticsResponses[sessionID]
+	if !ok {
+		return vpn.L2VPNSessionStatistics{}, errors.New("error session not found")
+	}
+	return res.SessionStatistics, res.Error
+}
+
+func (c *mockL2VPNClient) ListAllLogicalSwitches(ctx context.Context) ([]manager.LogicalSwitch, error) {
+	panic("unused function. Only used to satisfy L2VPNClient interface")
+}
+
+func buildL2VPNSessions() []vpn.L2VPNSession {
+	return []vpn.L2VPNSession{
+		{Id: fakeL2VPNSessionID, DisplayName: fakeL2VPNSessionID + "-name"},
+		{Id: fakeL2VPNOtherSessionID, DisplayName: fakeL2VPNOtherSessionID + "-name"},
+	}
+}
+
+func buildL2VPNTunnelStatus(status string) vpn.L2VPNTransportTunnelStatus {
+	return vpn.L2VPNTransportTunnelStatus{
+		ResourceType:  "IPSecVPNTransportStatus",
+		TunnelId:      &common.ResourceReference{TargetId: fakeL2VPNTunnelID, TargetDisplayName: fakeL2VPNTunnelID + "-name"},
+		SessionStatus: &vpn.IPSecVPNSessionStatus{SessionStatus: status},
+	}
+}
+
+func TestL2VPNCollector_GenerateL2VPNSessionStatusMetrics(t *testing.T) {
+	testcases := []struct {
+		description     string
+		responses       map[string]mockL2VPNStatusResponse
+		expectedMetrics []l2vpnSessionStatusMetric
+	}{
+		{
+			description: "Should return status of every session and its transport tunnels",
+			responses: map[string]mockL2VPNStatusResponse{
+				fakeL2VPNSessionID: {SessionStatus: vpn.L2VPNSessionStatus{
+					Status:           "UP",
+					TransportTunnels: []vpn.L2VPNTransportTunnelStatus{buildL2VPNTunnelStatus("UP")},
+				}},
+				fakeL2VPNOtherSessionID: {SessionStatus: vpn.L2VPNSessionStatus{
+					Status: "down",
+					TransportTunnels: []vpn.L2VPNTransportTunnelStatus{
+						buildL2VPNTunnelStatus("degraded"),
+						{ResourceType: "IPSecVPNTransportStatus"},
+					},
+				}},
+			},
+			expectedMetrics: []l2vpnSessionStatusMetric{
+				{
+					ID:           fakeL2VPNSessionID,
+					Name:         fakeL2VPNSessionID + "-name",
+					StatusDetail: buildExpectedVPNStatusDetail(l2vpnSessionPossibleStatus[:], "UP"),
+					Tunnels: []l2vpnTunnelStatusMetric{
+						{
+							ID:           fakeL2VPNTunnelID,
+							Name:         fakeL2VPNTunnelID + "-name",
+							StatusDetail: buildExpectedVPNStatusDetail(ipsecVPNSessionPossibleStatus[:], "UP"),
+						},
+					},
+				},
+				{
+					ID:           fakeL2VPNOtherSessionID,
+					Name:         fakeL2VPNOtherSessionID + "-name",
+					StatusDetail: buildExpectedVPNStatusDetail(l2vpnSessionPossibleStatus[:], "DOWN"),
+					Tunnels: []l2vpnTunnelStatusMetric{
+						{
+							ID:           fakeL2VPNTunnelID,
+							Name:         fakeL2VPNTunnelID + "-name",
+							StatusDetail: buildExpectedVPNStatusDetail(ipsecVPNSessionPossibleStatus[:], "DEGRADED"),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "Should only return status with valid response",
+			responses: map[string]mockL2VPNStatusResponse{
+				fakeL2VPNSessionID:      {SessionStatus: vpn.L2VPNSessionStatus{Status: "UP"}},
+				fakeL2VPNOtherSessionID: {Error: errors.New("error get session status")},
+			},
+			expectedMetrics: []l2vpnSessionStatusMetric{
+				{
+					ID:           fakeL2VPNSessionID,
+					Name:         fakeL2VPNSessionID + "-name",
+					StatusDetail: buildExpectedVPNStatusDetail(l2vpnSessionPossibleStatus[:], "UP"),
+				},
+			},
+		},
+		{
+			description:     "Should return empty metrics when empty response",
+			responses:       map[string]mockL2VPNStatusResponse{},
+			expectedMetrics: []l2vpnSessionStatusMetric{},
+		},
+	}
+	for _, tc := range testcases {
+		mockL2VPNClient := &mockL2VPNClient{
+			statusResponses: tc.responses,
+		}
+		l2vpnCollector := newL2VPNCollector(mockL2VPNClient, log.NewNopLogger())
+		sessionStatusMetrics := l2vpnCollector.generateL2VPNSessionStatusMetrics(context.Background(), buildL2VPNSessions())
+		assert.ElementsMatch(t, tc.expectedMetrics, sessionStatusMetrics, tc.description)
+	}
+}
+
+func TestL2VPNCollector_GenerateL2VPNLogicalSwitchStatisticMetrics(t *testing.T) {
+	logicalSwitchNames := map[string]string{
+		fakeL2VPNLogicalSwitchID: fakeL2VPNLogicalSwitchName,
+	}
+	testcases := []struct {
+		description     string
+		responses       map[string]mockL2VPNStatisticsResponse
+		expectedMetrics []l2vpnLogicalSwitchStatisticMetric
+	}{
+		{
+			description: "Should return statistics of every stretched logical switch",
+			responses: map[string]mockL2VPNStatisticsResponse{
+				fakeL2VPNSessionID: {SessionStatistics: vpn.L2VPNSessionStatistics{
+					TrafficStatisticsPerLogicalSwitch: []vpn.L2VPNPerLSTrafficStatistics{
+						{
+							LogicalSwitch: &common.ResourceReference{TargetId: fakeL2VPNLogicalSwitchID},
+							BytesIn:       1,
+							PacketsIn:     2,
+							BumBytesIn:    3,
+							BumPacketsIn:  4,
+							BytesOut:      5,
+							PacketsOut:    6,
+							BumBytesOut:   7,
+							BumPacketsOut: 8,
+						},
+					},
+				}},
+				fakeL2VPNOtherSessionID: {SessionStatistics: vpn.L2VPNSessionStatistics{
+					TrafficStatisticsPerLogicalSwitch: []vpn.L2VPNPerLSTrafficStatistics{
+						{LogicalSwitch: &common.ResourceReference{TargetId: "fake-unknown-logical-switch-id", TargetDisplayName: "fake-unknown-logical-switch-name"}},
+						{BytesIn: 1},
+					},
+				}},
+			},
+			expectedMetrics: []l2vpnLogicalSwitchStatisticMetric{
+				{
+					SessionID:         fakeL2VPNSessionID,
+					SessionName:       fakeL2VPNSessionID + "-name",
+					LogicalSwitchID:   fakeL2VPNLogicalSwitchID,
+					LogicalSwitchName: fakeL2VPNLogicalSwitchName,
+					RxByte:            1,
+					RxPacket:          2,
+					RxBumByte:         3,
+					RxBumPacket:       4,
+					TxByte:            5,
+					TxPacket:          6,
+					TxBumByte:         7,
+					TxBumPacket:       8,
+				},
+				{
+					SessionID:         fakeL2VPNOtherSessionID,
+					SessionName:       fakeL2VPNOtherSessionID + "-name",
+					LogicalSwitchID:   "fake-unknown-logical-switch-id",
+					LogicalSwitchName: "fake-unknown-logical-switch-name",
+				},
+			},
+		},
+		{
+			description: "Should only return statistics with valid response",
+			responses: map[string]mockL2VPNStatisticsResponse{
+				fakeL2VPNSessionID: {Error: errors.New("error get session statistics")},
+				fakeL2VPNOtherSessionID: {SessionStatistics: vpn.L2VPNSessionStatistics{
+					TrafficStatisticsPerLogicalSwitch: []vpn.L2VPNPerLSTrafficStatistics{
+						{LogicalSwitch: &common.ResourceReference{TargetId: fakeL2VPNLogicalSwitchID}, BytesIn: 1},
+					},
+				}},
+			},
+			expectedMetrics: []l2vpnLogicalSwitchStatisticMetric{
+				{
+					SessionID:         fakeL2VPNOtherSessionID,
+					SessionName:       fakeL2VPNOtherSessionID + "-name",
+					LogicalSwitchID:   fakeL2VPNLogicalSwitchID,
+					LogicalSwitchName: fakeL2VPNLogicalSwitchName,
+					RxByte:            1,
+				},
+			},
+		},
+		{
+			description:     "Should return empty metrics when empty response",
+			responses:       map[string]mockL2VPNStatisticsResponse{},
+			expectedMetrics: []l2vpnLogicalSwitchStatisticMetric{},
+		},
+	}
+	for _, tc := range testcases {
+		mockL2VPNClient := &mockL2VPNClient{
+			statisticsResponses: tc.responses,
+		}
+		l2vpnCollector := newL2VPNCollector(mockL2VPNClient, log.NewNopLogger())
+		logicalSwitchStatisticMetrics := l2vpnCollector.generateL2VPNLogicalSwitchStatisticMetrics(context.Background(), buildL2VPNSessions(), logicalSwitchNames)
+		assert.ElementsMatch(t, tc.expectedMetrics, logicalSwitchStatisticMetrics, tc.description)
+	}
+}
@@ -1,14 +1,16 @@
 package collector
 
 import (
+	"context"
+	"fmt"
 	"strings"
+	"sync"
 
 	"nsxt_exporter/client"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
-	nsxt "github.com/vmware/go-vmware-nsxt"
 	"github.com/vmware/go-vmware-nsxt/loadbalancer"
 )
 
@@ -17,7 +19,7 @@ var loadBalancerPoolPossibleStatus = []string{"UP", "PARTIALLY_UP", "PRIMARY_DOW
 var loadBalancerPoolMemberPossibleStatus = []string{"UP", "DOWN", "DISABLED", "GRACEFUL_DISABLED", "UNUSED"}
 
 func init() {
-	registerCollector("load_balancer", createLoadBalancerCollectorFactory)
+	registerCollector("load_balancer", defaultDisabled, createLoadBalancerCollectorFactory)
 }
 
 type loadBalancerCollector struct {
@@ -138,7 +140,7 @@ type loadBalancerPoolMemberStatisticMetric struct {
 	TotalSessions                float64
 }
 
-func createLoadBalancerCollectorFactory(apiClient *nsxt.APIClient, logger log.Logger) prometheus.Collector {
+func createLoadBalancerCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
 	nsxtClient := client.NewNSXTClient(apiClient, logger)
 	return newLoadBalancerCollector(nsxtClient, logger)
 }
@@ -406,7 +408,7 @@ func newLoadBalancerCollector(client client.LoadBalancerClient, logger log.Logge
 	}
 }
 
-// Describe implements the prometheus.Collector interface.
+// Describe implements the Collector interface.
 func (c *loadBalancerCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.loadBalancerStatus
 	ch <- c.loadBalancerPoolStatus
@@ -450,14 +452,13 @@ func (c *loadBalancerCollector) Describe(ch chan<- *prometheus.Desc) {
 	return
 }
 
-// Collect implements the prometheus.Collector interface.
-func (c *loadBalancerCollector) Collect(ch chan<- prometheus.Metric) {
-	loadBalancers, err := c.client.ListAllLoadBalancers()
+// Update implements the Collector interface.
+func (c *loadBalancerCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	loadBalancers, err := c.client.ListAllLoadBalancers(ctx)
 	if err != nil {
-		level.Error(c.logger).Log("msg", "Unable to list load balancers", "err", err)
-		return
+		return fmt.Errorf("unable to list load balancers: %s", err)
 	}
-	statusMetrics := c.generateLoadBalancerStatusMetrics(loadBalancers)
+	statusMetrics := c.generateLoadBalancerStatusMetrics(ctx, loadBalancers)
 	for _, metric := range statusMetrics {
 		for status, value := range metric.StatusDetail {
 			ch <- prometheus.MustNewConstMetric(c.loadBalancerStatus, prometheus.GaugeValue, value, metric.ID, metric.Name, status)
@@ -473,7 +474,7 @@ func (c *loadBalancerCollector) Collect(ch chan<- prometheus.Metric) {
 			}
 		}
 	}
-	statisticMetrics := c.generateLoadBalancerStatisticMetrics(loadBalancers)
+	statisticMetrics := c.generateLoadBalancerStatisticMetrics(ctx, loadBalancers)
 	for _, metric := range statisticMetrics {
 		ch <- prometheus.MustNewConstMetric(c.loadBalancerL4CurrentSessions, prometheus.GaugeValue, metric.L4CurrentSessions, metric.ID, metric.Name)
 		ch <- prometheus.MustNewConstMetric(c.loadBalancerL4MaxSessions, prometheus.GaugeValue, metric.L4MaxSessions, metric.ID, metric.Name)
@@ -518,15 +519,17 @@ func (c *loadBalancerCollector) Collect(ch chan<- prometheus.Metric) {
 			ch <- prometheus.MustNewConstMetric(c.loadBalancerVirtualServerTotalSessions, prometheus.GaugeValue, virtualServerStatistic.TotalSessions, virtualServerLabels...)
 		}
 	}
-	return
+	return nil
 }
 
-func (c *loadBalancerCollector) generateLoadBalancerStatusMetrics(loadBalancers []loadbalancer.LbService) (loadBalancerStatusMetrics []loadBalancerStatusMetric) {
-	for _, lb := range loadBalancers {
-		lbStatus, err := c.client.GetLoadBalancerStatus(lb.Id)
+func (c *loadBalancerCollector) generateLoadBalancerStatusMetrics(ctx context.Context, loadBalancers []loadbalancer.LbService) (loadBalancerStatusMetrics []loadBalancerStatusMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "load_balancer", len(loadBalancers), func(i int) {
+		lb := loadBalancers[i]
+		lbStatus, err := c.client.GetLoadBalancerStatus(ctx, lb.Id)
 		if err != nil {
 			level.Error(c.logger).Log("msg", "Unable to get load balancer status", "id", lb.Id, "err", err)
-			continue
+			return
 		}
 		loadBalancerStatusMetric := loadBalancerStatusMetric{
 			ID:           lbStatus.ServiceId,
@@ -548,8 +551,10 @@ func (c *loadBalancerCollector) generateLoadBalancerStatusMetrics(loadBalancers
 			}
 			loadBalancerStatusMetric.PoolsStatus = append(loadBalancerStatusMetric.PoolsStatus, poolStatusMetric)
 		}
+		mtx.Lock()
 		loadBalancerStatusMetrics = append(loadBalancerStatusMetrics, loadBalancerStatusMetric)
-	}
+		mtx.Unlock()
+	})
 	return
 }
 
@@ -565,12 +570,14 @@ func (c *loadBalancerCollector) constructStatusDetail(possibleStatus []string, c
 	return statusDetail
 }
 
-func (c *loadBalancerCollector) generateLoadBalancerStatisticMetrics(loadBalancers []loadbalancer.LbService) (loadBalancerStatisticMetrics []loadBalancerStatisticMetric) {
-	for _, lb := range loadBalancers {
-		lbStatistic, err := c.client.GetLoadBalancerStatistic(lb.Id)
+func (c *loadBalancerCollector) generateLoadBalancerStatisticMetrics(ctx context.Context, loadBalancers []loadbalancer.LbService) (loadBalancerStatisticMetrics []loadBalancerStatisticMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "load_balancer", len(loadBalancers), func(i int) {
+		lb := loadBalancers[i]
+		lbStatistic, err := c.client.GetLoadBalancerStatistic(ctx, lb.Id)
 		if err != nil {
 			level.Error(c.logger).Log("msg", "Unable to get load balancer statistic", "id", lb.Id, "err", err)
-			continue
+			return
 		}
 		loadBalancerStatisticMetric := loadBalancerStatisticMetric{
 			ID:                lb.Id,
@@ -634,7 +641,9 @@ func (c *loadBalancerCollector) generateLoadBalancerStatisticMetrics(loadBalance
 		}
 		loadBalancerStatisticMetric.loadBalancerPoolStatisticMetrics = loadBalancerPoolStatisticMetrics
 		loadBalancerStatisticMetric.loadBalancerVirtualServerStatisticMetrics = loadBalancerVirtualServerStatisticMetrics
+		mtx.Lock()
 		loadBalancerStatisticMetrics = append(loadBalancerStatisticMetrics, loadBalancerStatisticMetric)
-	}
+		mtx.Unlock()
+	})
 	return
 }
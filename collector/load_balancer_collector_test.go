@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -67,11 +68,11 @@ type mockLoadBalancerResponse struct {
 	Error            error
 }
 
-func (c *mockLoadBalancerClient) ListAllLoadBalancers() ([]loadbalancer.LbService, error) {
+func (c *mockLoadBalancerClient) ListAllLoadBalancers(ctx context.Context) ([]loadbalancer.LbService, error) {
 	panic("unused function. Only used to satisfy LoadBalancerClient interface")
 }
 
-func (c *mockLoadBalancerClient) GetLoadBalancerStatus(loadBalancerID string) (loadbalancer.LbServiceStatus, error) {
+func (c *mockLoadBalancerClient) GetLoadBalancerStatus(ctx context.Context, loadBalancerID string) (loadbalancer.LbServiceStatus, error) {
 	for _, res := range c.responses {
 		if res.ID == loadBalancerID {
 			return loadbalancer.LbServiceStatus{
@@ -96,7 +97,7 @@ func (c *mockLoadBalancerClient) GetLoadBalancerStatus(loadBalancerID string) (l
 	return loadbalancer.LbServiceStatus{}, errors.New("load balancer not found")
 }
 
-func (c *mockLoadBalancerClient) GetLoadBalancerStatistic(loadBalancerID string) (loadbalancer.LbServiceStatistics, error) {
+func (c *mockLoadBalancerClient) GetLoadBalancerStatistic(ctx context.Context, loadBalancerID string) (loadbalancer.LbServiceStatistics, error) {
 	for _, res := range c.responses {
 		if res.ID == loadBalancerID {
 			return loadbalancer.LbServiceStatistics{
@@ -471,7 +472,7 @@ func TestLoadBalancerCollector_GenerateLoadBalancerStatusMetrics(t *testing.T) {
 		loadBalancers := buildLoadBalancers(tc.loadBalancerResponses)
 		logger := log.NewNopLogger()
 		loadBalancerCollector := newLoadBalancerCollector(mockLoadBalancerClient, logger)
-		loadBalancerStatusMetrics := loadBalancerCollector.generateLoadBalancerStatusMetrics(loadBalancers)
+		loadBalancerStatusMetrics := loadBalancerCollector.generateLoadBalancerStatusMetrics(context.Background(), loadBalancers)
 		assert.ElementsMatch(t, tc.expectedMetrics, loadBalancerStatusMetrics, tc.description)
 	}
 }
@@ -512,7 +513,7 @@ func TestLoadBalancerCollector_GenerateLoadBalancerStatisticMetrics(t *testing.T
 		loadBalancers := buildLoadBalancers(tc.loadBalancerResponses)
 		logger := log.NewNopLogger()
 		loadBalancerCollector := newLoadBalancerCollector(client, logger)
-		loadBalancerStatisticMetrics := loadBalancerCollector.generateLoadBalancerStatisticMetrics(loadBalancers)
+		loadBalancerStatisticMetrics := loadBalancerCollector.generateLoadBalancerStatisticMetrics(context.Background(), loadBalancers)
 		assert.ElementsMatch(t, tc.expectedMetrics, loadBalancerStatisticMetrics, tc.description)
 	}
 }
@@ -1,21 +1,23 @@
 package collector
 
 import (
+	"context"
+	"fmt"
 	"strings"
+	"sync"
 
 	"nsxt_exporter/client"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
-	nsxt "github.com/vmware/go-vmware-nsxt"
 	"github.com/vmware/go-vmware-nsxt/manager"
 )
 
 var logicalPortPossibleStatus = [...]string{"UP", "DOWN", "UNKNOWN"}
 
 func init() {
-	registerCollector("logical_port", createLogicalPortCollectorFactory)
+	registerCollector("logical_port", defaultEnabled, createLogicalPortCollectorFactory)
 }
 
 type logicalPortCollector struct {
@@ -32,7 +34,7 @@ type logicalPortStatusMetric struct {
 	LogicalSwitchID string
 }
 
-func createLogicalPortCollectorFactory(apiClient *nsxt.APIClient, logger log.Logger) prometheus.Collector {
+func createLogicalPortCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
 	nsxtClient := client.NewNSXTClient(apiClient, logger)
 	return newLogicalPortCollector(nsxtClient, logger)
 }
@@ -52,14 +54,17 @@ func newLogicalPortCollector(logicalPortClient client.LogicalPortClient, logger
 	}
 }
 
-// Describe implements the prometheus.Collector interface.
+// Describe implements the Collector interface.
 func (lpc *logicalPortCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- lpc.logicalPortStatus
 }
 
-// Collect implements the prometheus.Collector interface.
-func (lpc *logicalPortCollector) Collect(ch chan<- prometheus.Metric) {
-	lportStatusMetrics := lpc.generateLogicalPortStatusMetrics()
+// Update implements the Collector interface.
+func (lpc *logicalPortCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	lportStatusMetrics, err := lpc.generateLogicalPortStatusMetrics(ctx)
+	if err != nil {
+		return err
+	}
 	for _, lportStatusMetric := range lportStatusMetrics {
 		for status, value := range lportStatusMetric.StatusDetail {
 			ch <- prometheus.MustNewConstMetric(
@@ -73,18 +78,18 @@ func (lpc *logicalPortCollector) Collect(ch chan<- prometheus.Metric) {
 			)
 		}
 	}
+	return nil
 }
 
-func (lpc *logicalPortCollector) generateLogicalPortStatusMetrics() (lportStatusMetrics []logicalPortStatusMetric) {
+func (lpc *logicalPortCollector) generateLogicalPortStatusMetrics(ctx context.Context) ([]logicalPortStatusMetric, error) {
 	var lports []manager.LogicalPort
 	var cursor string
 	for {
 		localVarOptionals := make(map[string]interface{})
 		localVarOptionals["cursor"] = cursor
-		lportsResult, err := lpc.logicalPortClient.ListLogicalPorts(localVarOptionals)
+		lportsResult, err := lpc.logicalPortClient.ListLogicalPorts(ctx, localVarOptionals)
 		if err != nil {
-			level.Error(lpc.logger).Log("msg", "Unable to list logical ports", "err", err)
-			return
+			return nil, fmt.Errorf("unable to list logical ports: %s", err)
 		}
 		lports = append(lports, lportsResult.Results...)
 		cursor = lportsResult.Cursor
@@ -92,11 +97,14 @@ func (lpc *logicalPortCollector) generateLogicalPortStatusMetrics() (lportStatus
 			break
 		}
 	}
-	for _, lport := range lports {
-		lportStatus, err := lpc.logicalPortClient.GetLogicalPortOperationalStatus(lport.Id, nil)
+	var lportStatusMetrics []logicalPortStatusMetric
+	var mtx sync.Mutex
+	forEach(ctx, "logical_port", len(lports), func(i int) {
+		lport := lports[i]
+		lportStatus, err := lpc.logicalPortClient.GetLogicalPortOperationalStatus(ctx, lport.Id, nil)
 		if err != nil {
 			level.Error(lpc.logger).Log("msg", "Unable to get logical port status", "id", lport.Id, "err", err)
-			continue
+			return
 		}
 		lportStatusMetric := logicalPortStatusMetric{
 			ID:              lport.Id,
@@ -111,7 +119,9 @@ func (lpc *logicalPortCollector) generateLogicalPortStatusMetrics() (lportStatus
 			}
 			lportStatusMetric.StatusDetail[possibleStatus] = statusValue
 		}
+		mtx.Lock()
 		lportStatusMetrics = append(lportStatusMetrics, lportStatusMetric)
-	}
-	return
+		mtx.Unlock()
+	})
+	return lportStatusMetrics, nil
 }
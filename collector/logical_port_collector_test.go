@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/go-kit/kit/log"
@@ -28,7 +29,7 @@ type mockLogicalPortResponse struct {
 	Error           error
 }
 
-func (c *mockLogicalPortClient) ListLogicalPorts(localVarOptionals map[string]interface{}) (manager.LogicalPortListResult, error) {
+func (c *mockLogicalPortClient) ListLogicalPorts(ctx context.Context, localVarOptionals map[string]interface{}) (manager.LogicalPortListResult, error) {
 	if c.logicalPortListError != nil {
 		return manager.LogicalPortListResult{}, c.logicalPortListError
 	}
@@ -46,7 +47,7 @@ func (c *mockLogicalPortClient) ListLogicalPorts(localVarOptionals map[string]in
 	}, nil
 }
 
-func (c *mockLogicalPortClient) GetLogicalPortOperationalStatus(lportID string, localVarOptionals map[string]interface{}) (manager.LogicalPortOperationalStatus, error) {
+func (c *mockLogicalPortClient) GetLogicalPortOperationalStatus(ctx context.Context, lportID string, localVarOptionals map[string]interface{}) (manager.LogicalPortOperationalStatus, error) {
 	for _, res := range c.responses {
 		if res.ID == lportID {
 			return manager.LogicalPortOperationalStatus{
@@ -147,7 +148,12 @@ func TestLogicalPortCollector_GenerateLogicalPortStatusMetrics(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		logicalPortCollector := newLogicalPortCollector(mockLogicalPortClient, logger)
-		logicalPortMetrics := logicalPortCollector.generateLogicalPortStatusMetrics()
+		logicalPortMetrics, err := logicalPortCollector.generateLogicalPortStatusMetrics(context.Background())
+		if testcase.logicalPortListError != nil {
+			assert.Error(t, err, testcase.description)
+		} else {
+			assert.NoError(t, err, testcase.description)
+		}
 		assert.ElementsMatch(t, testcase.expectedMetrics, logicalPortMetrics, testcase.description)
 	}
 }
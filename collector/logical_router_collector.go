@@ -1,20 +1,22 @@
 package collector
 
 import (
+	"context"
+	"fmt"
 	"nsxt_exporter/client"
 	"strings"
+	"sync"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
-	nsxt "github.com/vmware/go-vmware-nsxt"
 	"github.com/vmware/go-vmware-nsxt/manager"
 )
 
 var logicalRouterPossibleHAStatus = [...]string{"ACTIVE", "STANDBY"}
 
 func init() {
-	registerCollector("logical_router", createLogicalRouterCollectorFactory)
+	registerCollector("logical_router", defaultEnabled, createLogicalRouterCollectorFactory)
 }
 
 type logicalRouterCollector struct {
@@ -43,7 +45,7 @@ type natRuleStatisticMetric struct {
 	NatTotalBytes   float64
 }
 
-func createLogicalRouterCollectorFactory(apiClient *nsxt.APIClient, logger log.Logger) prometheus.Collector {
+func createLogicalRouterCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
 	nsxtClient := client.NewNSXTClient(apiClient, logger)
 	return newLogicalRouterCollector(nsxtClient, logger)
 }
@@ -82,33 +84,35 @@ func (c *logicalRouterCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.natRuleTotalBytes
 }
 
-func (c *logicalRouterCollector) Collect(ch chan<- prometheus.Metric) {
-	logicalRouters, err := c.logicalRouterClient.ListAllLogicalRouters()
+func (c *logicalRouterCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	logicalRouters, err := c.logicalRouterClient.ListAllLogicalRouters(ctx)
 	if err != nil {
-		level.Error(c.logger).Log("msg", "Unable to list logical routers", "err", err)
-		return
+		return fmt.Errorf("unable to list logical routers: %s", err)
 	}
-	logicalRouterStatusMetrics := c.generateLogicalRouterStatusMetrics(logicalRouters)
+	logicalRouterStatusMetrics := c.generateLogicalRouterStatusMetrics(ctx, logicalRouters)
 	for _, lrouterMetric := range logicalRouterStatusMetrics {
 		for haStatus, value := range lrouterMetric.HighAvailabilityStatusDetail {
 			labels := []string{lrouterMetric.ID, lrouterMetric.Name, lrouterMetric.TransportNodeID, lrouterMetric.ServiceRouterID, haStatus}
 			ch <- prometheus.MustNewConstMetric(c.logicalRouterStatus, prometheus.GaugeValue, value, labels...)
 		}
 	}
-	natRuleStatisticMetrics := c.generateNatRuleStatisticMetrics(logicalRouters)
+	natRuleStatisticMetrics := c.generateNatRuleStatisticMetrics(ctx, logicalRouters)
 	for _, natMetric := range natRuleStatisticMetrics {
 		labels := []string{natMetric.ID, natMetric.Name, natMetric.Type, natMetric.LogicalRouterID}
 		ch <- prometheus.MustNewConstMetric(c.natRuleTotalPackets, prometheus.GaugeValue, natMetric.NatTotalPackets, labels...)
 		ch <- prometheus.MustNewConstMetric(c.natRuleTotalBytes, prometheus.GaugeValue, natMetric.NatTotalBytes, labels...)
 	}
+	return nil
 }
 
-func (c *logicalRouterCollector) generateLogicalRouterStatusMetrics(logicalRouters []manager.LogicalRouter) (logicalRouterStatusMetrics []logicalRouterStatusMetric) {
-	for _, logicalRouter := range logicalRouters {
-		lrouterStatus, err := c.logicalRouterClient.GetLogicalRouterStatus(logicalRouter.Id)
+func (c *logicalRouterCollector) generateLogicalRouterStatusMetrics(ctx context.Context, logicalRouters []manager.LogicalRouter) (logicalRouterStatusMetrics []logicalRouterStatusMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "logical_router", len(logicalRouters), func(i int) {
+		logicalRouter := logicalRouters[i]
+		lrouterStatus, err := c.logicalRouterClient.GetLogicalRouterStatus(ctx, logicalRouter.Id)
 		if err != nil {
 			level.Error(c.logger).Log("msg", "Unable to get logical router status", "id", logicalRouter.Id, "err", err)
-			continue
+			return
 		}
 		for _, status := range lrouterStatus.PerNodeStatus {
 			logicalRouterStatusMetric := logicalRouterStatusMetric{
@@ -125,35 +129,52 @@ func (c *logicalRouterCollector) generateLogicalRouterStatusMetrics(logicalRoute
 				}
 				logicalRouterStatusMetric.HighAvailabilityStatusDetail[haStatus] = statusValue
 			}
+			mtx.Lock()
 			logicalRouterStatusMetrics = append(logicalRouterStatusMetrics, logicalRouterStatusMetric)
+			mtx.Unlock()
 		}
-	}
+	})
 	return
 }
 
-func (c *logicalRouterCollector) generateNatRuleStatisticMetrics(logicalRouters []manager.LogicalRouter) (natRuleStatisticMetrics []natRuleStatisticMetric) {
-	for _, logicalRouter := range logicalRouters {
-		natRules, err := c.logicalRouterClient.ListAllNatRules(logicalRouter.Id)
+func (c *logicalRouterCollector) generateNatRuleStatisticMetrics(ctx context.Context, logicalRouters []manager.LogicalRouter) (natRuleStatisticMetrics []natRuleStatisticMetric) {
+	type routerRule struct {
+		logicalRouterID string
+		rule            manager.NatRule
+	}
+	var mtx sync.Mutex
+	var routerRules []routerRule
+	forEach(ctx, "logical_router", len(logicalRouters), func(i int) {
+		logicalRouter := logicalRouters[i]
+		natRules, err := c.logicalRouterClient.ListAllNatRules(ctx, logicalRouter.Id)
 		if err != nil {
 			level.Error(c.logger).Log("msg", "Unable to get nat rules from logical router", "id", logicalRouter.Id, "err", err)
-			continue
+			return
 		}
+		mtx.Lock()
 		for _, rule := range natRules {
-			statistic, err := c.logicalRouterClient.GetNatStatisticsPerRule(logicalRouter.Id, rule.Id)
-			if err != nil {
-				level.Error(c.logger).Log("msg", "Unable to get nat rule statistics", "id", rule.Id, "logicalRouterID", logicalRouter.Id, "err", err)
-				continue
-			}
-			natRuleStatisticMetric := natRuleStatisticMetric{
-				ID:              rule.Id,
-				Name:            rule.DisplayName,
-				Type:            rule.Action,
-				LogicalRouterID: logicalRouter.Id,
-				NatTotalPackets: float64(statistic.TotalPackets),
-				NatTotalBytes:   float64(statistic.TotalBytes),
-			}
-			natRuleStatisticMetrics = append(natRuleStatisticMetrics, natRuleStatisticMetric)
+			routerRules = append(routerRules, routerRule{logicalRouterID: logicalRouter.Id, rule: rule})
 		}
-	}
+		mtx.Unlock()
+	})
+	forEach(ctx, "logical_router", len(routerRules), func(i int) {
+		logicalRouterID, rule := routerRules[i].logicalRouterID, routerRules[i].rule
+		statistic, err := c.logicalRouterClient.GetNatStatisticsPerRule(ctx, logicalRouterID, rule.Id)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "Unable to get nat rule statistics", "id", rule.Id, "logicalRouterID", logicalRouterID, "err", err)
+			return
+		}
+		natRuleStatisticMetric := natRuleStatisticMetric{
+			ID:              rule.Id,
+			Name:            rule.DisplayName,
+			Type:            rule.Action,
+			LogicalRouterID: logicalRouterID,
+			NatTotalPackets: float64(statistic.TotalPackets),
+			NatTotalBytes:   float64(statistic.TotalBytes),
+		}
+		mtx.Lock()
+		natRuleStatisticMetrics = append(natRuleStatisticMetrics, natRuleStatisticMetric)
+		mtx.Unlock()
+	})
 	return
 }
@@ -1,8 +1,10 @@
 package collector
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"nsxt_exporter/client/routing"
 	"testing"
 
 	"github.com/go-kit/kit/log"
@@ -37,11 +39,11 @@ type mockLogicalRouterResponse struct {
 	NatTotalBytes   int64
 }
 
-func (c *mockLogicalRouterClient) ListAllLogicalRouters() ([]manager.LogicalRouter, error) {
+func (c *mockLogicalRouterClient) ListAllLogicalRouters(ctx context.Context) ([]manager.LogicalRouter, error) {
 	panic("unused function. Only used to satisfy LogicalRouterClient interface")
 }
 
-func (c *mockLogicalRouterClient) GetLogicalRouterStatus(lrouterID string) (manager.LogicalRouterStatus, error) {
+func (c *mockLogicalRouterClient) GetLogicalRouterStatus(ctx context.Context, lrouterID string) (manager.LogicalRouterStatus, error) {
 	for _, res := range c.responses {
 		if res.LogicalRouter.Id == lrouterID {
 			return manager.LogicalRouterStatus{
@@ -53,7 +55,7 @@ func (c *mockLogicalRouterClient) GetLogicalRouterStatus(lrouterID string) (mana
 	return manager.LogicalRouterStatus{}, errors.New("error logical router not found")
 }
 
-func (c *mockLogicalRouterClient) ListAllNatRules(lrouterID string) ([]manager.NatRule, error) {
+func (c *mockLogicalRouterClient) ListAllNatRules(ctx context.Context, lrouterID string) ([]manager.NatRule, error) {
 	if c.natRuleListError != nil {
 		return nil, c.natRuleListError
 	}
@@ -75,7 +77,7 @@ func (c *mockLogicalRouterClient) ListAllNatRules(lrouterID string) ([]manager.N
 	return natRules, nil
 }
 
-func (c *mockLogicalRouterClient) GetNatStatisticsPerRule(lrouterID, ruleID string) (manager.NatStatisticsPerRule, error) {
+func (c *mockLogicalRouterClient) GetNatStatisticsPerRule(ctx context.Context, lrouterID, ruleID string) (manager.NatStatisticsPerRule, error) {
 	for _, res := range c.responses {
 		if res.LogicalRouter.Id != lrouterID {
 			continue
@@ -95,6 +97,22 @@ func (c *mockLogicalRouterClient) GetNatStatisticsPerRule(lrouterID, ruleID stri
 	return manager.NatStatisticsPerRule{}, errors.New("error nat rule not found")
 }
 
+func (c *mockLogicalRouterClient) ListAllBgpNeighborsStatus(ctx context.Context, lrouterID string) ([]manager.BgpNeighborStatus, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockLogicalRouterClient) ListAllBfdPeersStatus(ctx context.Context, lrouterID, transportNodeID string) ([]routing.BfdPeerStatus, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockLogicalRouterClient) ListAllRoutingTableRoutes(ctx context.Context, lrouterID, transportNodeID string) ([]manager.LogicalRouterRouteEntry, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockLogicalRouterClient) ListAllForwardingTableRoutes(ctx context.Context, lrouterID, transportNodeID string) ([]manager.LogicalRouterRouteEntry, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
 func buildLogicalRouterResponseWithStatus(lrouterID string, highAvailabilityStatus []string, err error) mockLogicalRouterResponse {
 	var lrouterStatus []manager.LogicalRouterStatusPerNode
 	for _, status := range highAvailabilityStatus {
@@ -216,7 +234,7 @@ func TestLogicalRouterCollector_GenerateLogicalRouterStatusMetrics(t *testing.T)
 		logger := log.NewNopLogger()
 		lrouterCollector := newLogicalRouterCollector(mockLogicalRouterClient, logger)
 		logicalRouters := buildLogicalRouters(tc.logicalRouterResponses)
-		metrics := lrouterCollector.generateLogicalRouterStatusMetrics(logicalRouters)
+		metrics := lrouterCollector.generateLogicalRouterStatusMetrics(context.Background(), logicalRouters)
 		assert.ElementsMatch(t, tc.expectedMetrics, metrics, tc.description)
 	}
 }
@@ -297,7 +315,7 @@ func TestLogicalRouterCollector_GenerateLogicalRouterNatStatisticMetrics(t *test
 		logger := log.NewNopLogger()
 		lrouterCollector := newLogicalRouterCollector(mockLogicalRouterClient, logger)
 		logicalRouters := buildLogicalRouters(tc.logicalRouterResponses)
-		metrics := lrouterCollector.generateNatRuleStatisticMetrics(logicalRouters)
+		metrics := lrouterCollector.generateNatRuleStatisticMetrics(context.Background(), logicalRouters)
 		assert.ElementsMatch(t, tc.expectedMetrics, metrics, tc.description)
 	}
 }
@@ -1,17 +1,19 @@
 package collector
 
 import (
+	"context"
+	"fmt"
 	"nsxt_exporter/client"
+	"sync"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
-	nsxt "github.com/vmware/go-vmware-nsxt"
 	"github.com/vmware/go-vmware-nsxt/manager"
 )
 
 func init() {
-	registerCollector("logical_router_port", createLogicalRouterPortCollectorFactory)
+	registerCollector("logical_router_port", defaultEnabled, createLogicalRouterPortCollectorFactory)
 }
 
 type logicalRouterPortCollector struct {
@@ -32,7 +34,7 @@ type logicalRouterPortStatisticMetric struct {
 	Tx                *manager.LogicalRouterPortCounters
 }
 
-func createLogicalRouterPortCollectorFactory(apiClient *nsxt.APIClient, logger log.Logger) prometheus.Collector {
+func createLogicalRouterPortCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
 	nsxtClient := client.NewNSXTClient(apiClient, logger)
 	return newLogicalRouterPortCollector(nsxtClient, logger)
 }
@@ -86,7 +88,7 @@ func newLogicalRouterPortCollector(logicalRouterPortClient client.LogicalRouterP
 	}
 }
 
-// Describe implements the prometheus.Collector interface.
+// Describe implements the Collector interface.
 func (c *logicalRouterPortCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.rxTotalPacket
 	ch <- c.rxDroppedPacket
@@ -96,9 +98,12 @@ func (c *logicalRouterPortCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.txTotalByte
 }
 
-// Collect implements the prometheus.Collector interface.
-func (c *logicalRouterPortCollector) Collect(ch chan<- prometheus.Metric) {
-	logicalRouterPortStatisticMetrics := c.generateLogicalRouterPortStatisticMetrics()
+// Update implements the Collector interface.
+func (c *logicalRouterPortCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	logicalRouterPortStatisticMetrics, err := c.generateLogicalRouterPortStatisticMetrics(ctx)
+	if err != nil {
+		return err
+	}
 	for _, metric := range logicalRouterPortStatisticMetrics {
 		ch <- c.buildLogicalRouterPortMetric(metric.LogicalRouterPort, c.rxTotalPacket, float64(metric.Rx.TotalPackets))
 		ch <- c.buildLogicalRouterPortMetric(metric.LogicalRouterPort, c.rxDroppedPacket, float64(metric.Rx.DroppedPackets))
@@ -107,6 +112,7 @@ func (c *logicalRouterPortCollector) Collect(ch chan<- prometheus.Metric) {
 		ch <- c.buildLogicalRouterPortMetric(metric.LogicalRouterPort, c.txDroppedPacket, float64(metric.Tx.DroppedPackets))
 		ch <- c.buildLogicalRouterPortMetric(metric.LogicalRouterPort, c.txTotalByte, float64(metric.Tx.TotalBytes))
 	}
+	return nil
 }
 
 func (c *logicalRouterPortCollector) buildLogicalRouterPortMetric(logicalRouterPort manager.LogicalRouterPort, desc *prometheus.Desc, value float64) prometheus.Metric {
@@ -120,25 +126,30 @@ func (c *logicalRouterPortCollector) buildLogicalRouterPortMetric(logicalRouterP
 	)
 }
 
-func (c *logicalRouterPortCollector) generateLogicalRouterPortStatisticMetrics() (logicalRouterPortStatisticMetrics []logicalRouterPortStatisticMetric) {
-	logicalRouterPorts, err := c.logicalRouterPortClient.ListAllLogicalRouterPorts()
+func (c *logicalRouterPortCollector) generateLogicalRouterPortStatisticMetrics(ctx context.Context) ([]logicalRouterPortStatisticMetric, error) {
+	logicalRouterPorts, err := c.logicalRouterPortClient.ListAllLogicalRouterPorts(ctx)
 	if err != nil {
-		level.Error(c.logger).Log("msg", "Unable to list logical ports", "err", err)
-		return
+		return nil, fmt.Errorf("unable to list logical router ports: %s", err)
 	}
 
-	for _, logicalRouterPort := range logicalRouterPorts {
-		statistic, err := c.logicalRouterPortClient.GetLogicalRouterPortStatisticsSummary(logicalRouterPort.Id)
+	var logicalRouterPortStatisticMetrics []logicalRouterPortStatisticMetric
+
+	var mtx sync.Mutex
+	forEach(ctx, "logical_router_port", len(logicalRouterPorts), func(i int) {
+		logicalRouterPort := logicalRouterPorts[i]
+		statistic, err := c.logicalRouterPortClient.GetLogicalRouterPortStatisticsSummary(ctx, logicalRouterPort.Id)
 		if err != nil {
 			level.Error(c.logger).Log("msg", "Unable to get logical router port statistics", "id", logicalRouterPort.Id, "err", err)
-			continue
+			return
 		}
 		logicalRouterPortStatisticMetric := logicalRouterPortStatisticMetric{
 			LogicalRouterPort: logicalRouterPort,
 			Rx:                statistic.Rx,
 			Tx:                statistic.Tx,
 		}
+		mtx.Lock()
 		logicalRouterPortStatisticMetrics = append(logicalRouterPortStatisticMetrics, logicalRouterPortStatisticMetric)
-	}
-	return
+		mtx.Unlock()
+	})
+	return logicalRouterPortStatisticMetrics, nil
 }
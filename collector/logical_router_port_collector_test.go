@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -35,7 +36,7 @@ type mockLogicalRouterPortResponse struct {
 	TxDroppedPackets int64
 }
 
-func (c *mockLogicalRouterPortClient) ListAllLogicalRouterPorts() ([]manager.LogicalRouterPort, error) {
+func (c *mockLogicalRouterPortClient) ListAllLogicalRouterPorts(ctx context.Context) ([]manager.LogicalRouterPort, error) {
 	if c.logicalRouterPortListError != nil {
 		return nil, c.logicalRouterPortListError
 	}
@@ -51,7 +52,7 @@ func (c *mockLogicalRouterPortClient) ListAllLogicalRouterPorts() ([]manager.Log
 	return logicalRouterPorts, nil
 }
 
-func (c *mockLogicalRouterPortClient) GetLogicalRouterPortStatisticsSummary(lrportID string) (manager.LogicalRouterPortStatisticsSummary, error) {
+func (c *mockLogicalRouterPortClient) GetLogicalRouterPortStatisticsSummary(ctx context.Context, lrportID string) (manager.LogicalRouterPortStatisticsSummary, error) {
 	for _, res := range c.responses {
 		if res.ID == lrportID {
 			return manager.LogicalRouterPortStatisticsSummary{
@@ -183,7 +184,12 @@ func TestLogicalRouterPortCollector_GenerateLogicalRouterPortStatisticMetrics(t
 		}
 		logger := log.NewNopLogger()
 		logicalRouterPortCollector := newLogicalRouterPortCollector(mockLogicalRouterPortClient, logger)
-		logicalRouterPortMetrics := logicalRouterPortCollector.generateLogicalRouterPortStatisticMetrics()
+		logicalRouterPortMetrics, err := logicalRouterPortCollector.generateLogicalRouterPortStatisticMetrics(context.Background())
+		if tc.logicalRouterPortListError != nil {
+			assert.Error(t, err, tc.description)
+		} else {
+			assert.NoError(t, err, tc.description)
+		}
 		assert.ElementsMatch(t, tc.expectedMetrics, logicalRouterPortMetrics, tc.description)
 	}
 }
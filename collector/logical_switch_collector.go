@@ -1,21 +1,23 @@
 package collector
 
 import (
+	"context"
+	"fmt"
 	"strings"
+	"sync"
 
 	"nsxt_exporter/client"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
-	nsxt "github.com/vmware/go-vmware-nsxt"
 	"github.com/vmware/go-vmware-nsxt/manager"
 )
 
 var logicalSwitchPossibleStatus = [...]string{"SUCCESS", "PARTIAL_SUCCESS", "IN_PROGRESS", "PENDING", "FAILED", "ORPHANED"}
 
 func init() {
-	registerCollector("logical_switch", createLogicalSwitchFactory)
+	registerCollector("logical_switch", defaultEnabled, createLogicalSwitchFactory)
 }
 
 type logicalSwitchCollector struct {
@@ -54,7 +56,7 @@ type logicalSwitchStatisticMetric struct {
 	TxPacketDropped float64
 }
 
-func createLogicalSwitchFactory(apiClient *nsxt.APIClient, logger log.Logger) prometheus.Collector {
+func createLogicalSwitchFactory(apiClient *client.APIClient, logger log.Logger) Collector {
 	nsxtClient := client.NewNSXTClient(apiClient, logger)
 	return newLogicalSwitchCollector(nsxtClient, logger)
 }
@@ -129,7 +131,7 @@ func newLogicalSwitchCollector(lswitchClient client.LogicalSwitchClient, logger
 	}
 }
 
-// Describe implements the prometheus.Collector interface.
+// Describe implements the Collector interface.
 func (c *logicalSwitchCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.logicalSwitchStatus
 	ch <- c.rxByteTotal
@@ -142,21 +144,20 @@ func (c *logicalSwitchCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.txPacketDropped
 }
 
-// Collect implements the prometheus.Collector interface.
-func (c *logicalSwitchCollector) Collect(ch chan<- prometheus.Metric) {
-	logicalSwitches, err := c.logicalSwitchClient.ListAllLogicalSwitches()
+// Update implements the Collector interface.
+func (c *logicalSwitchCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	logicalSwitches, err := c.logicalSwitchClient.ListAllLogicalSwitches(ctx)
 	if err != nil {
-		level.Error(c.logger).Log("msg", "Unable to list logical switches", "err", err)
-		return
+		return fmt.Errorf("unable to list logical switches: %s", err)
 	}
-	lswitchStatusMetrics := c.generateLogicalSwitchStatusMetrics(logicalSwitches)
+	lswitchStatusMetrics := c.generateLogicalSwitchStatusMetrics(ctx, logicalSwitches)
 	for _, m := range lswitchStatusMetrics {
 		for status, value := range m.StatusDetail {
 			labels := []string{m.ID, m.Name, m.TransportZoneID, status}
 			ch <- prometheus.MustNewConstMetric(c.logicalSwitchStatus, prometheus.GaugeValue, value, labels...)
 		}
 	}
-	lswitchStatisticMetrics := c.generateLogicalSwitchStatisticMetrics(logicalSwitches)
+	lswitchStatisticMetrics := c.generateLogicalSwitchStatisticMetrics(ctx, logicalSwitches)
 	for _, metric := range lswitchStatisticMetrics {
 		labels := []string{metric.ID, metric.Name, metric.TransportZoneID}
 		ch <- prometheus.MustNewConstMetric(c.rxByteTotal, prometheus.GaugeValue, metric.RxByteTotal, labels...)
@@ -168,14 +169,17 @@ func (c *logicalSwitchCollector) Collect(ch chan<- prometheus.Metric) {
 		ch <- prometheus.MustNewConstMetric(c.txPacketTotal, prometheus.GaugeValue, metric.TxPacketTotal, labels...)
 		ch <- prometheus.MustNewConstMetric(c.txPacketDropped, prometheus.GaugeValue, metric.TxPacketDropped, labels...)
 	}
+	return nil
 }
 
-func (c *logicalSwitchCollector) generateLogicalSwitchStatusMetrics(logicalSwitches []manager.LogicalSwitch) (logicalSwitchStatusMetrics []logicalSwitchStatusMetric) {
-	for _, logicalSwitch := range logicalSwitches {
-		logicalSwitchStatus, err := c.logicalSwitchClient.GetLogicalSwitchState(logicalSwitch.Id)
+func (c *logicalSwitchCollector) generateLogicalSwitchStatusMetrics(ctx context.Context, logicalSwitches []manager.LogicalSwitch) (logicalSwitchStatusMetrics []logicalSwitchStatusMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "logical_switch", len(logicalSwitches), func(i int) {
+		logicalSwitch := logicalSwitches[i]
+		logicalSwitchStatus, err := c.logicalSwitchClient.GetLogicalSwitchState(ctx, logicalSwitch.Id)
 		if err != nil {
 			level.Error(c.logger).Log("msg", "Unable to get logical switch status", "id", logicalSwitch.Id, "err", err)
-			continue
+			return
 		}
 		logicalSwitchStatusMetric := logicalSwitchStatusMetric{
 			ID:              logicalSwitch.Id,
@@ -190,17 +194,21 @@ func (c *logicalSwitchCollector) generateLogicalSwitchStatusMetrics(logicalSwitc
 			}
 			logicalSwitchStatusMetric.StatusDetail[possibleStatus] = statusValue
 		}
+		mtx.Lock()
 		logicalSwitchStatusMetrics = append(logicalSwitchStatusMetrics, logicalSwitchStatusMetric)
-	}
+		mtx.Unlock()
+	})
 	return
 }
 
-func (c *logicalSwitchCollector) generateLogicalSwitchStatisticMetrics(logicalSwitches []manager.LogicalSwitch) (logicalSwitchStatisticMetrics []logicalSwitchStatisticMetric) {
-	for _, logicalSwitch := range logicalSwitches {
-		logicalSwitchStatistic, err := c.logicalSwitchClient.GetLogicalSwitchStatistic(logicalSwitch.Id)
+func (c *logicalSwitchCollector) generateLogicalSwitchStatisticMetrics(ctx context.Context, logicalSwitches []manager.LogicalSwitch) (logicalSwitchStatisticMetrics []logicalSwitchStatisticMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "logical_switch", len(logicalSwitches), func(i int) {
+		logicalSwitch := logicalSwitches[i]
+		logicalSwitchStatistic, err := c.logicalSwitchClient.GetLogicalSwitchStatistic(ctx, logicalSwitch.Id)
 		if err != nil {
 			level.Error(c.logger).Log("msg", "Unable to get logical switch statistic", "id", logicalSwitch.Id, "err", err)
-			continue
+			return
 		}
 		logicalSwitchStatisticMetric := logicalSwitchStatisticMetric{
 			ID:              logicalSwitch.Id,
@@ -215,7 +223,9 @@ func (c *logicalSwitchCollector) generateLogicalSwitchStatisticMetrics(logicalSw
 			TxPacketTotal:   float64(logicalSwitchStatistic.TxPackets.Total),
 			TxPacketDropped: float64(logicalSwitchStatistic.TxPackets.Dropped),
 		}
+		mtx.Lock()
 		logicalSwitchStatisticMetrics = append(logicalSwitchStatisticMetrics, logicalSwitchStatisticMetric)
-	}
+		mtx.Unlock()
+	})
 	return
 }
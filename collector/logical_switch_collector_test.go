@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -26,11 +27,11 @@ type mockLogicalSwitchResponse struct {
 	Error          error
 }
 
-func (c *mockLogicalSwitchClient) ListAllLogicalSwitches() ([]manager.LogicalSwitch, error) {
+func (c *mockLogicalSwitchClient) ListAllLogicalSwitches(ctx context.Context) ([]manager.LogicalSwitch, error) {
 	panic("unused function. Only used to satisfy LogicalSwitchClient interface")
 }
 
-func (c *mockLogicalSwitchClient) GetLogicalSwitchState(lswitchID string) (manager.LogicalSwitchState, error) {
+func (c *mockLogicalSwitchClient) GetLogicalSwitchState(ctx context.Context, lswitchID string) (manager.LogicalSwitchState, error) {
 	for _, res := range c.responses {
 		if res.logicalSwitch.Id == lswitchID {
 			return manager.LogicalSwitchState{
@@ -41,7 +42,7 @@ func (c *mockLogicalSwitchClient) GetLogicalSwitchState(lswitchID string) (manag
 	return manager.LogicalSwitchState{}, errors.New("error")
 }
 
-func (c *mockLogicalSwitchClient) GetLogicalSwitchStatistic(lswitchID string) (manager.LogicalSwitchStatistics, error) {
+func (c *mockLogicalSwitchClient) GetLogicalSwitchStatistic(ctx context.Context, lswitchID string) (manager.LogicalSwitchStatistics, error) {
 	for _, res := range c.responses {
 		if res.logicalSwitch.Id == lswitchID {
 			dataCounter := &manager.DataCounter{
@@ -196,7 +197,7 @@ func TestLogicalSwitchCollector_GenerateLogicalSwitchStatusMetrics(t *testing.T)
 		for _, res := range tc.lswitchResponses {
 			logicalSwitches = append(logicalSwitches, res.logicalSwitch)
 		}
-		lswitchMetrics := lswitchCollector.generateLogicalSwitchStatusMetrics(logicalSwitches)
+		lswitchMetrics := lswitchCollector.generateLogicalSwitchStatusMetrics(context.Background(), logicalSwitches)
 		assert.ElementsMatch(t, tc.expectedMetrics, lswitchMetrics, tc.description)
 	}
 }
@@ -277,7 +278,7 @@ func TestLogicalSwitchCollector_GenerateLogicalSwitchStatisticsMetrics(t *testin
 		for _, res := range tc.lswitchResponses {
 			logicalSwitches = append(logicalSwitches, res.logicalSwitch)
 		}
-		metrics := lswitchCollector.generateLogicalSwitchStatisticMetrics(logicalSwitches)
+		metrics := lswitchCollector.generateLogicalSwitchStatisticMetrics(context.Background(), logicalSwitches)
 		assert.ElementsMatch(t, tc.expectedMetrics, metrics, tc.description)
 	}
 }
@@ -0,0 +1,251 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"nsxt_exporter/client"
+	"nsxt_exporter/client/policy"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func init() {
+	registerCollector("policy_firewall", defaultDisabled, createPolicyFirewallCollectorFactory)
+}
+
+type policyFirewallCollector struct {
+	policyFirewallClient client.PolicyFirewallClient
+	logger               log.Logger
+
+	ruleHits               *prometheus.Desc
+	rulePackets            *prometheus.Desc
+	ruleBytes              *prometheus.Desc
+	ruleSessions           *prometheus.Desc
+	rulePopularityIndex    *prometheus.Desc
+	ruleMaxPopularityIndex *prometheus.Desc
+}
+
+// policyFirewallSecurityPolicy is a security policy of a domain.
+type policyFirewallSecurityPolicy struct {
+	DomainID       string
+	SecurityPolicy policy.SecurityPolicy
+}
+
+type policyFirewallRuleStatisticMetric struct {
+	Path               string
+	PolicyPath         string
+	Category           string
+	RuleID             string
+	RuleName           string
+	Action             string
+	Hits               float64
+	Packets            float64
+	Bytes              float64
+	Sessions           float64
+	PopularityIndex    float64
+	MaxPopularityIndex float64
+}
+
+func createPolicyFirewallCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
+	policyClient := client.NewPolicyClient(apiClient, logger)
+	return newPolicyFirewallCollector(policyClient, logger)
+}
+
+func newPolicyFirewallCollector(policyFirewallClient client.PolicyFirewallClient, logger log.Logger) *policyFirewallCollector {
+	labels := []string{"path", "policy_path", "category", "rule_id", "rule_name", "action"}
+	ruleHits := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_firewall", "rule_hits"),
+		"Total hits received by the distributed firewall rule",
+		labels,
+		nil,
+	)
+	rulePackets := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_firewall", "rule_packets"),
+		"Total packets processed by the distributed firewall rule",
+		labels,
+		nil,
+	)
+	ruleBytes := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_firewall", "rule_bytes"),
+		"Total bytes processed by the distributed firewall rule",
+		labels,
+		nil,
+	)
+	ruleSessions := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_firewall", "rule_sessions"),
+		"Total sessions processed by the distributed firewall rule",
+		labels,
+		nil,
+	)
+	rulePopularityIndex := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_firewall", "rule_popularity_index"),
+		"Popularity index of the distributed firewall rule",
+		labels,
+		nil,
+	)
+	ruleMaxPopularityIndex := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_firewall", "rule_max_popularity_index"),
+		"Maximum popularity index of all distributed firewall rules of the type",
+		labels,
+		nil,
+	)
+	return &policyFirewallCollector{
+		policyFirewallClient:   policyFirewallClient,
+		logger:                 logger,
+		ruleHits:               ruleHits,
+		rulePackets:            rulePackets,
+		ruleBytes:              ruleBytes,
+		ruleSessions:           ruleSessions,
+		rulePopularityIndex:    rulePopularityIndex,
+		ruleMaxPopularityIndex: ruleMaxPopularityIndex,
+	}
+}
+
+// Describe implements the Collector interface.
+func (c *policyFirewallCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.ruleHits
+	ch <- c.rulePackets
+	ch <- c.ruleBytes
+	ch <- c.ruleSessions
+	ch <- c.rulePopularityIndex
+	ch <- c.ruleMaxPopularityIndex
+}
+
+// Update implements the Collector interface.
+func (c *policyFirewallCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	var errs []string
+	securityPolicies, err := c.listSecurityPolicies(ctx)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	ruleStatisticMetrics, err := c.generatePolicyFirewallRuleStatisticMetrics(ctx, securityPolicies)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	for _, m := range ruleStatisticMetrics {
+		labels := []string{m.Path, m.PolicyPath, m.Category, m.RuleID, m.RuleName, m.Action}
+		ch <- prometheus.MustNewConstMetric(c.ruleHits, prometheus.GaugeValue, m.Hits, labels...)
+		ch <- prometheus.MustNewConstMetric(c.rulePackets, prometheus.GaugeValue, m.Packets, labels...)
+		ch <- prometheus.MustNewConstMetric(c.ruleBytes, prometheus.GaugeValue, m.Bytes, labels...)
+		ch <- prometheus.MustNewConstMetric(c.ruleSessions, prometheus.GaugeValue, m.Sessions, labels...)
+		ch <- prometheus.MustNewConstMetric(c.rulePopularityIndex, prometheus.GaugeValue, m.PopularityIndex, labels...)
+		ch <- prometheus.MustNewConstMetric(c.ruleMaxPopularityIndex, prometheus.GaugeValue, m.MaxPopularityIndex, labels...)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// listSecurityPolicies returns the security policies of all domains. Domains
+// whose security policies cannot be listed are skipped and reported in the
+// returned error.
+func (c *policyFirewallCollector) listSecurityPolicies(ctx context.Context) ([]policyFirewallSecurityPolicy, error) {
+	domains, err := c.policyFirewallClient.ListAllDomains(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list domains: %s", err)
+	}
+	var mtx sync.Mutex
+	var errs []string
+	var securityPolicies []policyFirewallSecurityPolicy
+	forEach(ctx, "policy_firewall", len(domains), func(i int) {
+		domain := domains[i]
+		domainSecurityPolicies, err := c.policyFirewallClient.ListAllSecurityPolicies(ctx, domain.Id)
+		if err != nil {
+			mtx.Lock()
+			errs = append(errs, fmt.Sprintf("unable to list security policies of domain %s: %s", domain.Id, err))
+			mtx.Unlock()
+			return
+		}
+		mtx.Lock()
+		for _, securityPolicy := range domainSecurityPolicies {
+			securityPolicies = append(securityPolicies, policyFirewallSecurityPolicy{DomainID: domain.Id, SecurityPolicy: securityPolicy})
+		}
+		mtx.Unlock()
+	})
+	if len(errs) > 0 {
+		return securityPolicies, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return securityPolicies, nil
+}
+
+func (c *policyFirewallCollector) generatePolicyFirewallRuleStatisticMetrics(ctx context.Context, securityPolicies []policyFirewallSecurityPolicy) (ruleStatisticMetrics []policyFirewallRuleStatisticMetric, err error) {
+	var mtx sync.Mutex
+	var errs []string
+	forEach(ctx, "policy_firewall", len(securityPolicies), func(i int) {
+		domainID, securityPolicy := securityPolicies[i].DomainID, securityPolicies[i].SecurityPolicy
+		rules, err := c.policyFirewallClient.ListAllRules(ctx, domainID, securityPolicy.Id)
+		if err != nil {
+			mtx.Lock()
+			errs = append(errs, fmt.Sprintf("unable to list rules of security policy %s: %s", securityPolicy.Path, err))
+			mtx.Unlock()
+			return
+		}
+		statistics, err := c.policyFirewallClient.GetSecurityPolicyStatistics(ctx, domainID, securityPolicy.Id)
+		if err != nil {
+			mtx.Lock()
+			errs = append(errs, fmt.Sprintf("unable to get statistic of security policy %s: %s", securityPolicy.Path, err))
+			mtx.Unlock()
+			return
+		}
+		ruleMetrics := make(map[string]*policyFirewallRuleStatisticMetric)
+		var policyRuleMetrics []*policyFirewallRuleStatisticMetric
+		for _, rule := range rules {
+			// Rules are labelled by path, as the numeric ID is only assigned
+			// once the rule is realized.
+			path := rule.Path
+			if path == "" {
+				path = securityPolicy.Path + "/rules/" + rule.Id
+			}
+			ruleMetric := &policyFirewallRuleStatisticMetric{
+				Path:       path,
+				PolicyPath: securityPolicy.Path,
+				Category:   securityPolicy.Category,
+				RuleName:   rule.DisplayName,
+				Action:     rule.Action,
+			}
+			ruleMetrics[path] = ruleMetric
+			if rule.RuleId != 0 {
+				ruleMetric.RuleID = strconv.FormatInt(rule.RuleId, 10)
+				ruleMetrics[ruleMetric.RuleID] = ruleMetric
+			}
+			policyRuleMetrics = append(policyRuleMetrics, ruleMetric)
+		}
+		// Counts are summed and popularity indexes maxed across enforcement
+		// points. Statistics reference rules by path or numeric ID.
+		for _, enforcementPointStatistics := range statistics {
+			for _, ruleStatistics := range enforcementPointStatistics.Statistics.Results {
+				ruleMetric, ok := ruleMetrics[ruleStatistics.Rule]
+				if !ok {
+					ruleMetric, ok = ruleMetrics[ruleStatistics.InternalRuleId]
+				}
+				if !ok {
+					continue
+				}
+				ruleMetric.Hits += float64(ruleStatistics.HitCount)
+				ruleMetric.Packets += float64(ruleStatistics.PacketCount)
+				ruleMetric.Bytes += float64(ruleStatistics.ByteCount)
+				ruleMetric.Sessions += float64(ruleStatistics.SessionCount)
+				if popularityIndex := float64(ruleStatistics.PopularityIndex); popularityIndex > ruleMetric.PopularityIndex {
+					ruleMetric.PopularityIndex = popularityIndex
+				}
+				if maxPopularityIndex := float64(ruleStatistics.MaxPopularityIndex); maxPopularityIndex > ruleMetric.MaxPopularityIndex {
+					ruleMetric.MaxPopularityIndex = maxPopularityIndex
+				}
+			}
+		}
+		mtx.Lock()
+		for _, ruleMetric := range policyRuleMetrics {
+			ruleStatisticMetrics = append(ruleStatisticMetrics, *ruleMetric)
+		}
+		mtx.Unlock()
+	})
+	if len(errs) > 0 {
+		err = fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return
+}
@@ -0,0 +1,250 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"nsxt_exporter/client/policy"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	fakePolicyFirewallDomainID   = "default"
+	fakePolicyFirewallPolicyPath = "/infra/domains/default/security-policies/"
+)
+
+type mockPolicyFirewallClient struct {
+	domains              []policy.Domain
+	domainListError      error
+	securityPolicies     map[string][]policy.SecurityPolicy
+	securityPolicyErrors map[string]error
+	responses            map[string]mockPolicyFirewallResponse
+}
+
+type mockPolicyFirewallResponse struct {
+	Rules          []policy.Rule
+	RuleListError  error
+	Statistics     []policy.SecurityPolicyStatistics
+	StatisticError error
+}
+
+func (c *mockPolicyFirewallClient) ListAllDomains(ctx context.Context) ([]policy.Domain, error) {
+	return c.domains, c.domainListError
+}
+
+func (c *mockPolicyFirewallClient) ListAllSecurityPolicies(ctx context.Context, domainID string) ([]policy.SecurityPolicy, error) {
+	if err := c.securityPolicyErrors[domainID]; err != nil {
+		return nil, err
+	}
+	return c.securityPolicies[domainID], nil
+}
+
+func (c *mockPolicyFirewallClient) ListAllRules(ctx context.Context, domainID, securityPolicyID string) ([]policy.Rule, error) {
+	res, ok := c.responses[securityPolicyID]
+	if !ok {
+		return nil, errors.New("error security policy not found")
+	}
+	return res.Rules, res.RuleListError
+}
+
+func (c *mockPolicyFirewallClient) GetSecurityPolicyStatistics(ctx context.Context, domainID, securityPolicyID string) ([]policy.SecurityPolicyStatistics, error) {
+	res, ok := c.responses[securityPolicyID]
+	if !ok {
+		return nil, errors.New("error security policy not found")
+	}
+	return res.Statistics, res.StatisticError
+}
+
+func buildPolicyFirewallSecurityPolicy(id, category string) policyFirewallSecurityPolicy {
+	return policyFirewallSecurityPolicy{
+		DomainID: fakePolicyFirewallDomainID,
+		SecurityPolicy: policy.SecurityPolicy{
+			Id:       id,
+			Path:     fakePolicyFirewallPolicyPath + id,
+			Category: category,
+		},
+	}
+}
+
+func buildPolicyFirewallRule(policyID, ruleID string, ruleNumber int64, action string) policy.Rule {
+	return policy.Rule{
+		Id:          ruleID,
+		DisplayName: ruleID + "-name",
+		Path:        fakePolicyFirewallPolicyPath + policyID + "/rules/" + ruleID,
+		Action:      action,
+		RuleId:      ruleNumber,
+	}
+}
+
+func buildPolicyFirewallStatistics(ruleStatistics ...policy.RuleStatistics) policy.SecurityPolicyStatistics {
+	return policy.SecurityPolicyStatistics{
+		EnforcementPointPath: "/infra/sites/default/enforcement-points/default",
+		Statistics:           policy.RuleStatisticsListResult{Results: ruleStatistics},
+	}
+}
+
+func TestPolicyFirewallCollector_ListSecurityPolicies(t *testing.T) {
+	testcases := []struct {
+		description              string
+		domainListError          error
+		securityPolicyErrors     map[string]error
+		expectedSecurityPolicies []policyFirewallSecurityPolicy
+		expectedError            bool
+	}{
+		{
+			description: "Should return security policies of every domain",
+			expectedSecurityPolicies: []policyFirewallSecurityPolicy{
+				buildPolicyFirewallSecurityPolicy("policy-a", "Application"),
+				{DomainID: "other", SecurityPolicy: policy.SecurityPolicy{Id: "policy-b", Path: "/infra/domains/other/security-policies/policy-b", Category: "Emergency"}},
+			},
+		},
+		{
+			description:     "Should return error when listing domains fails",
+			domainListError: errors.New("error list domains"),
+			expectedError:   true,
+		},
+		{
+			description:          "Should return remaining security policies and error when listing security policies of a domain fails",
+			securityPolicyErrors: map[string]error{"other": errors.New("error list security policies")},
+			expectedSecurityPolicies: []policyFirewallSecurityPolicy{
+				buildPolicyFirewallSecurityPolicy("policy-a", "Application"),
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range testcases {
+		mockPolicyFirewallClient := &mockPolicyFirewallClient{
+			domains:         []policy.Domain{{Id: fakePolicyFirewallDomainID}, {Id: "other"}},
+			domainListError: tc.domainListError,
+			securityPolicies: map[string][]policy.SecurityPolicy{
+				fakePolicyFirewallDomainID: {buildPolicyFirewallSecurityPolicy("policy-a", "Application").SecurityPolicy},
+				"other":                    {{Id: "policy-b", Path: "/infra/domains/other/security-policies/policy-b", Category: "Emergency"}},
+			},
+			securityPolicyErrors: tc.securityPolicyErrors,
+		}
+		firewallCollector := newPolicyFirewallCollector(mockPolicyFirewallClient, log.NewNopLogger())
+		securityPolicies, err := firewallCollector.listSecurityPolicies(context.Background())
+		assert.Equal(t, tc.expectedError, err != nil, tc.description)
+		assert.ElementsMatch(t, tc.expectedSecurityPolicies, securityPolicies, tc.description)
+	}
+}
+
+func TestPolicyFirewallCollector_GeneratePolicyFirewallRuleStatisticMetrics(t *testing.T) {
+	securityPolicies := []policyFirewallSecurityPolicy{
+		buildPolicyFirewallSecurityPolicy("policy-a", "Application"),
+		buildPolicyFirewallSecurityPolicy("policy-b", "Infrastructure"),
+	}
+	testcases := []struct {
+		description     string
+		responses       map[string]mockPolicyFirewallResponse
+		expectedMetrics []policyFirewallRuleStatisticMetric
+		expectedError   bool
+	}{
+		{
+			description: "Should return statistics of every rule",
+			responses: map[string]mockPolicyFirewallResponse{
+				"policy-a": {
+					Rules: []policy.Rule{
+						buildPolicyFirewallRule("policy-a", "rule-1", 1001, "ALLOW"),
+						buildPolicyFirewallRule("policy-a", "rule-2", 1002, "DROP"),
+					},
+					Statistics: []policy.SecurityPolicyStatistics{
+						buildPolicyFirewallStatistics(
+							policy.RuleStatistics{Rule: fakePolicyFirewallPolicyPath + "policy-a/rules/rule-1", HitCount: 1, PacketCount: 2, ByteCount: 3, SessionCount: 4, PopularityIndex: 5, MaxPopularityIndex: 6},
+							policy.RuleStatistics{InternalRuleId: "1002", HitCount: 10, PacketCount: 20, ByteCount: 30, SessionCount: 40, PopularityIndex: 50, MaxPopularityIndex: 60},
+						),
+					},
+				},
+				"policy-b": {
+					Rules: []policy.Rule{
+						buildPolicyFirewallRule("policy-b", "rule-3", 1003, "REJECT"),
+					},
+				},
+			},
+			expectedMetrics: []policyFirewallRuleStatisticMetric{
+				{Path: fakePolicyFirewallPolicyPath + "policy-a/rules/rule-1", PolicyPath: fakePolicyFirewallPolicyPath + "policy-a", Category: "Application", RuleID: "1001", RuleName: "rule-1-name", Action: "ALLOW", Hits: 1, Packets: 2, Bytes: 3, Sessions: 4, PopularityIndex: 5, MaxPopularityIndex: 6},
+				{Path: fakePolicyFirewallPolicyPath + "policy-a/rules/rule-2", PolicyPath: fakePolicyFirewallPolicyPath + "policy-a", Category: "Application", RuleID: "1002", RuleName: "rule-2-name", Action: "DROP", Hits: 10, Packets: 20, Bytes: 30, Sessions: 40, PopularityIndex: 50, MaxPopularityIndex: 60},
+				{Path: fakePolicyFirewallPolicyPath + "policy-b/rules/rule-3", PolicyPath: fakePolicyFirewallPolicyPath + "policy-b", Category: "Infrastructure", RuleID: "1003", RuleName: "rule-3-name", Action: "REJECT"},
+			},
+		},
+		{
+			description: "Should aggregate statistics across enforcement points",
+			responses: map[string]mockPolicyFirewallResponse{
+				"policy-a": {
+					Rules: []policy.Rule{
+						buildPolicyFirewallRule("policy-a", "rule-1", 1001, "ALLOW"),
+					},
+					Statistics: []policy.SecurityPolicyStatistics{
+						buildPolicyFirewallStatistics(policy.RuleStatistics{InternalRuleId: "1001", HitCount: 1, PacketCount: 2, ByteCount: 3, SessionCount: 4, PopularityIndex: 5, MaxPopularityIndex: 60}),
+						buildPolicyFirewallStatistics(policy.RuleStatistics{InternalRuleId: "1001", HitCount: 10, PacketCount: 20, ByteCount: 30, SessionCount: 40, PopularityIndex: 50, MaxPopularityIndex: 6}),
+					},
+				},
+				"policy-b": {},
+			},
+			expectedMetrics: []policyFirewallRuleStatisticMetric{
+				{Path: fakePolicyFirewallPolicyPath + "policy-a/rules/rule-1", PolicyPath: fakePolicyFirewallPolicyPath + "policy-a", Category: "Application", RuleID: "1001", RuleName: "rule-1-name", Action: "ALLOW", Hits: 11, Packets: 22, Bytes: 33, Sessions: 44, PopularityIndex: 50, MaxPopularityIndex: 60},
+			},
+		},
+		{
+			description: "Should label rules that are not realized yet by path",
+			responses: map[string]mockPolicyFirewallResponse{
+				"policy-a": {
+					Rules: []policy.Rule{
+						{Id: "rule-4", DisplayName: "rule-4-name", Path: fakePolicyFirewallPolicyPath + "policy-a/rules/rule-4", Action: "ALLOW"},
+						{Id: "rule-5", DisplayName: "rule-5-name", Action: "DROP"},
+					},
+					Statistics: []policy.SecurityPolicyStatistics{
+						buildPolicyFirewallStatistics(policy.RuleStatistics{Rule: fakePolicyFirewallPolicyPath + "policy-a/rules/rule-5", HitCount: 1}),
+					},
+				},
+				"policy-b": {},
+			},
+			expectedMetrics: []policyFirewallRuleStatisticMetric{
+				{Path: fakePolicyFirewallPolicyPath + "policy-a/rules/rule-4", PolicyPath: fakePolicyFirewallPolicyPath + "policy-a", Category: "Application", RuleName: "rule-4-name", Action: "ALLOW"},
+				{Path: fakePolicyFirewallPolicyPath + "policy-a/rules/rule-5", PolicyPath: fakePolicyFirewallPolicyPath + "policy-a", Category: "Application", RuleName: "rule-5-name", Action: "DROP", Hits: 1},
+			},
+		},
+		{
+			description: "Should return error when security policy rules or statistics cannot be retrieved",
+			responses: map[string]mockPolicyFirewallResponse{
+				"policy-a": {
+					Rules:          []policy.Rule{buildPolicyFirewallRule("policy-a", "rule-1", 1001, "ALLOW")},
+					StatisticError: errors.New("error get security policy statistics"),
+				},
+				"policy-b": {
+					Rules:         []policy.Rule{buildPolicyFirewallRule("policy-b", "rule-3", 1003, "REJECT")},
+					RuleListError: errors.New("error list rules"),
+				},
+			},
+			expectedMetrics: []policyFirewallRuleStatisticMetric{},
+			expectedError:   true,
+		},
+		{
+			description: "Should return remaining statistics and error when a security policy fails",
+			responses: map[string]mockPolicyFirewallResponse{
+				"policy-a": {
+					Rules:          []policy.Rule{buildPolicyFirewallRule("policy-a", "rule-1", 1001, "ALLOW")},
+					StatisticError: errors.New("error get security policy statistics"),
+				},
+				"policy-b": {
+					Rules: []policy.Rule{buildPolicyFirewallRule("policy-b", "rule-3", 1003, "REJECT")},
+				},
+			},
+			expectedMetrics: []policyFirewallRuleStatisticMetric{
+				{Path: fakePolicyFirewallPolicyPath + "policy-b/rules/rule-3", PolicyPath: fakePolicyFirewallPolicyPath + "policy-b", Category: "Infrastructure", RuleID: "1003", RuleName: "rule-3-name", Action: "REJECT"},
+			},
+			expectedError: true,
+		},
+	}
+	for _, tc := range testcases {
+		mockPolicyFirewallClient := &mockPolicyFirewallClient{
+			responses: tc.responses,
+		}
+		firewallCollector := newPolicyFirewallCollector(mockPolicyFirewallClient, log.NewNopLogger())
+		ruleStatisticMetrics, err := firewallCollector.generatePolicyFirewallRuleStatisticMetrics(context.Background(), securityPolicies)
+		assert.Equal(t, tc.expectedError, err != nil, tc.description)
+		assert.ElementsMatch(t, tc.expectedMetrics, ruleStatisticMetrics, tc.description)
+	}
+}
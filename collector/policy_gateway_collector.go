@@ -0,0 +1,247 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"nsxt_exporter/client"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vmware/go-vmware-nsxt/manager"
+)
+
+var (
+	policyPossibleState           = [...]string{"pending", "in_progress", "success", "partial_success", "failed", "error", "orphaned", "in_sync", "unknown"}
+	policyGatewayPossibleHAStatus = [...]string{"ACTIVE", "STANDBY", "DOWN", "SYNC", "UNKNOWN"}
+)
+
+func init() {
+	registerCollector("policy_gateway", defaultDisabled, createPolicyGatewayCollectorFactory)
+}
+
+type policyGatewayCollector struct {
+	policyGatewayClient client.PolicyGatewayClient
+	logger              log.Logger
+
+	gatewayInfo         *prometheus.Desc
+	gatewayState        *prometheus.Desc
+	gatewayEdgeState    *prometheus.Desc
+	gatewayEdgeHAStatus *prometheus.Desc
+}
+
+// policyGateway is a Tier-0 or Tier-1 gateway.
+type policyGateway struct {
+	ID     string
+	Path   string
+	Name   string
+	Tier   string
+	HAMode string
+}
+
+type policyGatewayStateMetric struct {
+	Path        string
+	Name        string
+	Tier        string
+	StateDetail map[string]float64
+}
+
+type policyGatewayEdgeStateMetric struct {
+	Path        string
+	Name        string
+	Tier        string
+	SubsystemID string
+	StateDetail map[string]float64
+}
+
+type policyGatewayEdgeHAStatusMetric struct {
+	Path                         string
+	Name                         string
+	Tier                         string
+	TransportNodeID              string
+	ServiceRouterID              string
+	HighAvailabilityStatusDetail map[string]float64
+}
+
+func createPolicyGatewayCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
+	policyClient := client.NewPolicyClient(apiClient, logger)
+	return newPolicyGatewayCollector(policyClient, logger)
+}
+
+func newPolicyGatewayCollector(policyGatewayClient client.PolicyGatewayClient, logger log.Logger) *policyGatewayCollector {
+	gatewayInfo := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_gateway", "info"),
+		"Information of Tier-0 and Tier-1 gateway",
+		[]string{"path", "name", "tier", "ha_mode"},
+		nil,
+	)
+	gatewayState := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_gateway", "state"),
+		"Realized state of Tier-0 and Tier-1 gateway",
+		[]string{"path", "name", "tier", "state"},
+		nil,
+	)
+	gatewayEdgeState := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_gateway", "edge_state"),
+		"Realized state of Tier-0 and Tier-1 gateway on subsystem such as transport node",
+		[]string{"path", "name", "tier", "subsystem_id", "state"},
+		nil,
+	)
+	gatewayEdgeHAStatus := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_gateway", "edge_high_availability_status"),
+		"High availability status of Tier-0 and Tier-1 gateway service router on transport node",
+		[]string{"path", "name", "tier", "transport_node_id", "service_router_id", "high_availability_status"},
+		nil,
+	)
+	return &policyGatewayCollector{
+		policyGatewayClient: policyGatewayClient,
+		logger:              logger,
+		gatewayInfo:         gatewayInfo,
+		gatewayState:        gatewayState,
+		gatewayEdgeState:    gatewayEdgeState,
+		gatewayEdgeHAStatus: gatewayEdgeHAStatus,
+	}
+}
+
+// Describe implements the Collector interface.
+func (c *policyGatewayCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.gatewayInfo
+	ch <- c.gatewayState
+	ch <- c.gatewayEdgeState
+	ch <- c.gatewayEdgeHAStatus
+}
+
+// Update implements the Collector interface.
+func (c *policyGatewayCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	gateways, err := c.listGateways(ctx)
+	if err != nil {
+		return err
+	}
+	for _, gateway := range gateways {
+		ch <- prometheus.MustNewConstMetric(c.gatewayInfo, prometheus.GaugeValue, 1, gateway.Path, gateway.Name, gateway.Tier, gateway.HAMode)
+	}
+	stateMetrics, edgeStateMetrics, edgeHAStatusMetrics := c.generatePolicyGatewayStateMetrics(ctx, gateways)
+	for _, m := range stateMetrics {
+		for state, value := range m.StateDetail {
+			ch <- prometheus.MustNewConstMetric(c.gatewayState, prometheus.GaugeValue, value, m.Path, m.Name, m.Tier, state)
+		}
+	}
+	for _, m := range edgeStateMetrics {
+		for state, value := range m.StateDetail {
+			ch <- prometheus.MustNewConstMetric(c.gatewayEdgeState, prometheus.GaugeValue, value, m.Path, m.Name, m.Tier, m.SubsystemID, state)
+		}
+	}
+	for _, m := range edgeHAStatusMetrics {
+		for haStatus, value := range m.HighAvailabilityStatusDetail {
+			ch <- prometheus.MustNewConstMetric(c.gatewayEdgeHAStatus, prometheus.GaugeValue, value, m.Path, m.Name, m.Tier, m.TransportNodeID, m.ServiceRouterID, haStatus)
+		}
+	}
+	return nil
+}
+
+func (c *policyGatewayCollector) listGateways(ctx context.Context) ([]policyGateway, error) {
+	tier0s, err := c.policyGatewayClient.ListAllTier0s(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list tier-0 gateways: %s", err)
+	}
+	tier1s, err := c.policyGatewayClient.ListAllTier1s(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list tier-1 gateways: %s", err)
+	}
+	var gateways []policyGateway
+	for _, tier0 := range tier0s {
+		gateways = append(gateways, policyGateway{
+			ID:     tier0.Id,
+			Path:   tier0.Path,
+			Name:   tier0.DisplayName,
+			Tier:   "tier0",
+			HAMode: tier0.HaMode,
+		})
+	}
+	for _, tier1 := range tier1s {
+		gateways = append(gateways, policyGateway{
+			ID:     tier1.Id,
+			Path:   tier1.Path,
+			Name:   tier1.DisplayName,
+			Tier:   "tier1",
+			HAMode: tier1.HaMode,
+		})
+	}
+	return gateways, nil
+}
+
+func (c *policyGatewayCollector) getGatewayState(ctx context.Context, gateway policyGateway) (manager.ConfigurationState, manager.LogicalRouterStatus, error) {
+	if gateway.Tier == "tier0" {
+		state, err := c.policyGatewayClient.GetTier0State(ctx, gateway.ID)
+		return state.Tier0State, state.Tier0Status, err
+	}
+	state, err := c.policyGatewayClient.GetTier1State(ctx, gateway.ID)
+	return state.Tier1State, state.Tier1Status, err
+}
+
+func (c *policyGatewayCollector) generatePolicyGatewayStateMetrics(ctx context.Context, gateways []policyGateway) (stateMetrics []policyGatewayStateMetric, edgeStateMetrics []policyGatewayEdgeStateMetric, edgeHAStatusMetrics []policyGatewayEdgeHAStatusMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "policy_gateway", len(gateways), func(i int) {
+		gateway := gateways[i]
+		state, status, err := c.getGatewayState(ctx, gateway)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "Unable to get gateway state", "path", gateway.Path, "err", err)
+			return
+		}
+		stateMetric := policyGatewayStateMetric{
+			Path:        gateway.Path,
+			Name:        gateway.Name,
+			Tier:        gateway.Tier,
+			StateDetail: policyStateDetail(state.State),
+		}
+		var edgeStates []policyGatewayEdgeStateMetric
+		for _, detail := range state.Details {
+			edgeStates = append(edgeStates, policyGatewayEdgeStateMetric{
+				Path:        gateway.Path,
+				Name:        gateway.Name,
+				Tier:        gateway.Tier,
+				SubsystemID: detail.SubSystemId,
+				StateDetail: policyStateDetail(detail.State),
+			})
+		}
+		var edgeHAStatuses []policyGatewayEdgeHAStatusMetric
+		for _, nodeStatus := range status.PerNodeStatus {
+			edgeHAStatus := policyGatewayEdgeHAStatusMetric{
+				Path:                         gateway.Path,
+				Name:                         gateway.Name,
+				Tier:                         gateway.Tier,
+				TransportNodeID:              nodeStatus.TransportNodeId,
+				ServiceRouterID:              nodeStatus.ServiceRouterId,
+				HighAvailabilityStatusDetail: make(map[string]float64),
+			}
+			for _, haStatus := range policyGatewayPossibleHAStatus {
+				statusValue := 0.0
+				if haStatus == strings.ToUpper(nodeStatus.HighAvailabilityStatus) {
+					statusValue = 1.0
+				}
+				edgeHAStatus.HighAvailabilityStatusDetail[haStatus] = statusValue
+			}
+			edgeHAStatuses = append(edgeHAStatuses, edgeHAStatus)
+		}
+		mtx.Lock()
+		stateMetrics = append(stateMetrics, stateMetric)
+		edgeStateMetrics = append(edgeStateMetrics, edgeStates...)
+		edgeHAStatusMetrics = append(edgeHAStatusMetrics, edgeHAStatuses...)
+		mtx.Unlock()
+	})
+	return
+}
+
+func policyStateDetail(state string) map[string]float64 {
+	stateDetail := make(map[string]float64)
+	for _, possibleState := range policyPossibleState {
+		stateValue := 0.0
+		if possibleState == strings.ToLower(state) {
+			stateValue = 1.0
+		}
+		stateDetail[possibleState] = stateValue
+	}
+	return stateDetail
+}
@@ -0,0 +1,203 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"nsxt_exporter/client/policy"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/go-vmware-nsxt/manager"
+)
+
+const (
+	fakePolicyGatewayTransportNodeID = "fake-transport-node-id"
+	fakePolicyGatewaySubsystemID     = "fake-subsystem-id"
+	fakePolicyGatewayServiceRouterID = "fake-service-router-id"
+)
+
+type mockPolicyGatewayClient struct {
+	tier0s         []policy.Tier0
+	tier1s         []policy.Tier1
+	tier0ListError error
+	tier1ListError error
+	responses      map[string]mockPolicyGatewayResponse
+}
+
+type mockPolicyGatewayResponse struct {
+	State    string
+	HAStatus string
+	Error    error
+}
+
+func (c *mockPolicyGatewayClient) ListAllTier0s(ctx context.Context) ([]policy.Tier0, error) {
+	return c.tier0s, c.tier0ListError
+}
+
+func (c *mockPolicyGatewayClient) GetTier0State(ctx context.Context, tier0ID string) (policy.Tier0GatewayState, error) {
+	state, status, err := c.gatewayState(tier0ID)
+	return policy.Tier0GatewayState{Tier0State: state, Tier0Status: status}, err
+}
+
+func (c *mockPolicyGatewayClient) ListAllTier1s(ctx context.Context) ([]policy.Tier1, error) {
+	return c.tier1s, c.tier1ListError
+}
+
+func (c *mockPolicyGatewayClient) GetTier1State(ctx context.Context, tier1ID string) (policy.Tier1GatewayState, error) {
+	state, status, err := c.gatewayState(tier1ID)
+	return policy.Tier1GatewayState{Tier1State: state, Tier1Status: status}, err
+}
+
+func (c *mockPolicyGatewayClient) gatewayState(id string) (manager.ConfigurationState, manager.LogicalRouterStatus, error) {
+	res, ok := c.responses[id]
+	if !ok {
+		return manager.ConfigurationState{}, manager.LogicalRouterStatus{}, errors.New("error gateway not found")
+	}
+	state := manager.ConfigurationState{
+		State: res.State,
+		Details: []manager.ConfigurationStateElement{
+			{State: res.State, SubSystemId: fakePolicyGatewaySubsystemID, SubSystemType: "TransportNode"},
+		},
+	}
+	status := manager.LogicalRouterStatus{
+		PerNodeStatus: []manager.LogicalRouterStatusPerNode{
+			{HighAvailabilityStatus: res.HAStatus, ServiceRouterId: fakePolicyGatewayServiceRouterID, TransportNodeId: fakePolicyGatewayTransportNodeID},
+		},
+	}
+	return state, status, res.Error
+}
+
+func buildExpectedPolicyStateDetail(nonZeroState string) map[string]float64 {
+	stateDetail := make(map[string]float64)
+	for _, state := range policyPossibleState {
+		stateDetail[state] = 0.0
+	}
+	stateDetail[nonZeroState] = 1.0
+	return stateDetail
+}
+
+func buildExpectedPolicyGatewayHAStatusDetail(nonZeroStatus string) map[string]float64 {
+	statusDetail := map[string]float64{
+		"ACTIVE":  0.0,
+		"STANDBY": 0.0,
+		"DOWN":    0.0,
+		"SYNC":    0.0,
+		"UNKNOWN": 0.0,
+	}
+	statusDetail[nonZeroStatus] = 1.0
+	return statusDetail
+}
+
+func TestPolicyGatewayCollector_ListGateways(t *testing.T) {
+	tier0s := []policy.Tier0{{Id: "t0", Path: "/infra/tier-0s/t0", DisplayName: "t0-name", HaMode: "ACTIVE_STANDBY"}}
+	tier1s := []policy.Tier1{{Id: "t1", Path: "/infra/tier-1s/t1", DisplayName: "t1-name"}}
+	testcases := []struct {
+		description      string
+		tier0ListError   error
+		tier1ListError   error
+		expectedGateways []policyGateway
+		expectedError    bool
+	}{
+		{
+			description: "Should return tier-0 and tier-1 gateways",
+			expectedGateways: []policyGateway{
+				{ID: "t0", Path: "/infra/tier-0s/t0", Name: "t0-name", Tier: "tier0", HAMode: "ACTIVE_STANDBY"},
+				{ID: "t1", Path: "/infra/tier-1s/t1", Name: "t1-name", Tier: "tier1"},
+			},
+		},
+		{
+			description:    "Should return error when listing tier-0 gateways fails",
+			tier0ListError: errors.New("error list tier-0 gateways"),
+			expectedError:  true,
+		},
+		{
+			description:    "Should return error when listing tier-1 gateways fails",
+			tier1ListError: errors.New("error list tier-1 gateways"),
+			expectedError:  true,
+		},
+	}
+	for _, tc := range testcases {
+		mockPolicyGatewayClient := &mockPolicyGatewayClient{
+			tier0s:         tier0s,
+			tier1s:         tier1s,
+			tier0ListError: tc.tier0ListError,
+			tier1ListError: tc.tier1ListError,
+		}
+		gatewayCollector := newPolicyGatewayCollector(mockPolicyGatewayClient, log.NewNopLogger())
+		gateways, err := gatewayCollector.listGateways(context.Background())
+		if tc.expectedError {
+			assert.Error(t, err, tc.description)
+			continue
+		}
+		assert.NoError(t, err, tc.description)
+		assert.ElementsMatch(t, tc.expectedGateways, gateways, tc.description)
+	}
+}
+
+func TestPolicyGatewayCollector_GeneratePolicyGatewayStateMetrics(t *testing.T) {
+	gateways := []policyGateway{
+		{ID: "t0", Path: "/infra/tier-0s/t0", Name: "t0-name", Tier: "tier0"},
+		{ID: "t1", Path: "/infra/tier-1s/t1", Name: "t1-name", Tier: "tier1"},
+	}
+	testcases := []struct {
+		description                 string
+		responses                   map[string]mockPolicyGatewayResponse
+		expectedStateMetrics        []policyGatewayStateMetric
+		expectedEdgeStateMetrics    []policyGatewayEdgeStateMetric
+		expectedEdgeHAStatusMetrics []policyGatewayEdgeHAStatusMetric
+	}{
+		{
+			description: "Should return state metrics of every gateway",
+			responses: map[string]mockPolicyGatewayResponse{
+				"t0": {State: "success", HAStatus: "ACTIVE"},
+				"t1": {State: "IN_PROGRESS", HAStatus: "standby"},
+			},
+			expectedStateMetrics: []policyGatewayStateMetric{
+				{Path: "/infra/tier-0s/t0", Name: "t0-name", Tier: "tier0", StateDetail: buildExpectedPolicyStateDetail("success")},
+				{Path: "/infra/tier-1s/t1", Name: "t1-name", Tier: "tier1", StateDetail: buildExpectedPolicyStateDetail("in_progress")},
+			},
+			expectedEdgeStateMetrics: []policyGatewayEdgeStateMetric{
+				{Path: "/infra/tier-0s/t0", Name: "t0-name", Tier: "tier0", SubsystemID: fakePolicyGatewaySubsystemID, StateDetail: buildExpectedPolicyStateDetail("success")},
+				{Path: "/infra/tier-1s/t1", Name: "t1-name", Tier: "tier1", SubsystemID: fakePolicyGatewaySubsystemID, StateDetail: buildExpectedPolicyStateDetail("in_progress")},
+			},
+			expectedEdgeHAStatusMetrics: []policyGatewayEdgeHAStatusMetric{
+				{Path: "/infra/tier-0s/t0", Name: "t0-name", Tier: "tier0", TransportNodeID: fakePolicyGatewayTransportNodeID, ServiceRouterID: fakePolicyGatewayServiceRouterID, HighAvailabilityStatusDetail: buildExpectedPolicyGatewayHAStatusDetail("ACTIVE")},
+				{Path: "/infra/tier-1s/t1", Name: "t1-name", Tier: "tier1", TransportNodeID: fakePolicyGatewayTransportNodeID, ServiceRouterID: fakePolicyGatewayServiceRouterID, HighAvailabilityStatusDetail: buildExpectedPolicyGatewayHAStatusDetail("STANDBY")},
+			},
+		},
+		{
+			description: "Should only return state metrics with valid response",
+			responses: map[string]mockPolicyGatewayResponse{
+				"t0": {State: "failed", HAStatus: "DOWN", Error: errors.New("error get gateway state")},
+				"t1": {State: "success", HAStatus: "ACTIVE"},
+			},
+			expectedStateMetrics: []policyGatewayStateMetric{
+				{Path: "/infra/tier-1s/t1", Name: "t1-name", Tier: "tier1", StateDetail: buildExpectedPolicyStateDetail("success")},
+			},
+			expectedEdgeStateMetrics: []policyGatewayEdgeStateMetric{
+				{Path: "/infra/tier-1s/t1", Name: "t1-name", Tier: "tier1", SubsystemID: fakePolicyGatewaySubsystemID, StateDetail: buildExpectedPolicyStateDetail("success")},
+			},
+			expectedEdgeHAStatusMetrics: []policyGatewayEdgeHAStatusMetric{
+				{Path: "/infra/tier-1s/t1", Name: "t1-name", Tier: "tier1", TransportNodeID: fakePolicyGatewayTransportNodeID, ServiceRouterID: fakePolicyGatewayServiceRouterID, HighAvailabilityStatusDetail: buildExpectedPolicyGatewayHAStatusDetail("ACTIVE")},
+			},
+		},
+		{
+			description:                 "Should return empty metrics when empty response",
+			responses:                   map[string]mockPolicyGatewayResponse{},
+			expectedStateMetrics:        []policyGatewayStateMetric{},
+			expectedEdgeStateMetrics:    []policyGatewayEdgeStateMetric{},
+			expectedEdgeHAStatusMetrics: []policyGatewayEdgeHAStatusMetric{},
+		},
+	}
+	for _, tc := range testcases {
+		mockPolicyGatewayClient := &mockPolicyGatewayClient{
+			responses: tc.responses,
+		}
+		gatewayCollector := newPolicyGatewayCollector(mockPolicyGatewayClient, log.NewNopLogger())
+		stateMetrics, edgeStateMetrics, edgeHAStatusMetrics := gatewayCollector.generatePolicyGatewayStateMetrics(context.Background(), gateways)
+		assert.ElementsMatch(t, tc.expectedStateMetrics, stateMetrics, tc.description)
+		assert.ElementsMatch(t, tc.expectedEdgeStateMetrics, edgeStateMetrics, tc.description)
+		assert.ElementsMatch(t, tc.expectedEdgeHAStatusMetrics, edgeHAStatusMetrics, tc.description)
+	}
+}
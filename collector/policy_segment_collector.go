@@ -0,0 +1,316 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"nsxt_exporter/client"
+	"nsxt_exporter/client/policy"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vmware/go-vmware-nsxt/manager"
+)
+
+var policySegmentPossibleAdminState = [...]string{"UP", "DOWN"}
+
+func init() {
+	registerCollector("policy_segment", defaultDisabled, createPolicySegmentCollectorFactory)
+}
+
+type policySegmentCollector struct {
+	policySegmentClient client.PolicySegmentClient
+	logger              log.Logger
+
+	segmentInfo       *prometheus.Desc
+	segmentAdminState *prometheus.Desc
+	segmentState      *prometheus.Desc
+	segmentPorts      *prometheus.Desc
+	rxByteTotal       *prometheus.Desc
+	rxByteDropped     *prometheus.Desc
+	rxPacketTotal     *prometheus.Desc
+	rxPacketDropped   *prometheus.Desc
+	txByteTotal       *prometheus.Desc
+	txByteDropped     *prometheus.Desc
+	txPacketTotal     *prometheus.Desc
+	txPacketDropped   *prometheus.Desc
+}
+
+type policySegmentStateMetric struct {
+	Path        string
+	Name        string
+	StateDetail map[string]float64
+}
+
+type policySegmentPortMetric struct {
+	Path  string
+	Name  string
+	Ports float64
+}
+
+type policySegmentStatisticMetric struct {
+	Path            string
+	Name            string
+	RxByteTotal     float64
+	RxByteDropped   float64
+	RxPacketTotal   float64
+	RxPacketDropped float64
+	TxByteTotal     float64
+	TxByteDropped   float64
+	TxPacketTotal   float64
+	TxPacketDropped float64
+}
+
+func createPolicySegmentCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
+	policyClient := client.NewPolicyClient(apiClient, logger)
+	return newPolicySegmentCollector(policyClient, logger)
+}
+
+func newPolicySegmentCollector(policySegmentClient client.PolicySegmentClient, logger log.Logger) *policySegmentCollector {
+	segmentInfo := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_segment", "info"),
+		"Information of segment",
+		[]string{"path", "name", "gateway_path", "transport_zone_path"},
+		nil,
+	)
+	segmentAdminState := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_segment", "admin_state"),
+		"Admin state of segment",
+		[]string{"path", "name", "admin_state"},
+		nil,
+	)
+	segmentState := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_segment", "state"),
+		"Realized state of segment",
+		[]string{"path", "name", "state"},
+		nil,
+	)
+	segmentPorts := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_segment", "ports"),
+		"Number of ports on segment",
+		[]string{"path", "name"},
+		nil,
+	)
+	rxByteTotal := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_segment", "rx_byte"),
+		"Total bytes received (rx) on segment across enforcement points",
+		[]string{"path", "name"},
+		nil,
+	)
+	rxByteDropped := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_segment", "rx_dropped_byte"),
+		"Total receive (rx) bytes dropped on segment across enforcement points",
+		[]string{"path", "name"},
+		nil,
+	)
+	rxPacketTotal := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_segment", "rx_packet"),
+		"Total packets received (rx) on segment across enforcement points",
+		[]string{"path", "name"},
+		nil,
+	)
+	rxPacketDropped := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_segment", "rx_dropped_packet"),
+		"Total receive (rx) packets dropped on segment across enforcement points",
+		[]string{"path", "name"},
+		nil,
+	)
+	txByteTotal := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_segment", "tx_byte"),
+		"Total bytes transmitted (tx) on segment across enforcement points",
+		[]string{"path", "name"},
+		nil,
+	)
+	txByteDropped := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_segment", "tx_dropped_byte"),
+		"Total transmit (tx) bytes dropped on segment across enforcement points",
+		[]string{"path", "name"},
+		nil,
+	)
+	txPacketTotal := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_segment", "tx_packet"),
+		"Total packets transmitted (tx) on segment across enforcement points",
+		[]string{"path", "name"},
+		nil,
+	)
+	txPacketDropped := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "policy_segment", "tx_dropped_packet"),
+		"Total transmit (tx) packets dropped on segment across enforcement points",
+		[]string{"path", "name"},
+		nil,
+	)
+	return &policySegmentCollector{
+		policySegmentClient: policySegmentClient,
+		logger:              logger,
+		segmentInfo:         segmentInfo,
+		segmentAdminState:   segmentAdminState,
+		segmentState:        segmentState,
+		segmentPorts:        segmentPorts,
+		rxByteTotal:         rxByteTotal,
+		rxByteDropped:       rxByteDropped,
+		rxPacketTotal:       rxPacketTotal,
+		rxPacketDropped:     rxPacketDropped,
+		txByteTotal:         txByteTotal,
+		txByteDropped:       txByteDropped,
+		txPacketTotal:       txPacketTotal,
+		txPacketDropped:     txPacketDropped,
+	}
+}
+
+// Describe implements the Collector interface.
+func (c *policySegmentCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.segmentInfo
+	ch <- c.segmentAdminState
+	ch <- c.segmentState
+	ch <- c.segmentPorts
+	ch <- c.rxByteTotal
+	ch <- c.rxByteDropped
+	ch <- c.rxPacketTotal
+	ch <- c.rxPacketDropped
+	ch <- c.txByteTotal
+	ch <- c.txByteDropped
+	ch <- c.txPacketTotal
+	ch <- c.txPacketDropped
+}
+
+// Update implements the Collector interface.
+func (c *policySegmentCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	segments, err := c.policySegmentClient.ListAllSegments(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list segments: %s", err)
+	}
+	for _, segment := range segments {
+		ch <- prometheus.MustNewConstMetric(c.segmentInfo, prometheus.GaugeValue, 1, segment.Path, segment.DisplayName, segment.ConnectivityPath, segment.TransportZonePath)
+		for adminState, value := range policySegmentAdminStateDetail(segment.AdminState) {
+			ch <- prometheus.MustNewConstMetric(c.segmentAdminState, prometheus.GaugeValue, value, segment.Path, segment.DisplayName, adminState)
+		}
+	}
+	stateMetrics := c.generatePolicySegmentStateMetrics(ctx, segments)
+	for _, m := range stateMetrics {
+		for state, value := range m.StateDetail {
+			ch <- prometheus.MustNewConstMetric(c.segmentState, prometheus.GaugeValue, value, m.Path, m.Name, state)
+		}
+	}
+	portMetrics := c.generatePolicySegmentPortMetrics(ctx, segments)
+	for _, m := range portMetrics {
+		ch <- prometheus.MustNewConstMetric(c.segmentPorts, prometheus.GaugeValue, m.Ports, m.Path, m.Name)
+	}
+	statisticMetrics := c.generatePolicySegmentStatisticMetrics(ctx, segments)
+	for _, m := range statisticMetrics {
+		labels := []string{m.Path, m.Name}
+		ch <- prometheus.MustNewConstMetric(c.rxByteTotal, prometheus.GaugeValue, m.RxByteTotal, labels...)
+		ch <- prometheus.MustNewConstMetric(c.rxByteDropped, prometheus.GaugeValue, m.RxByteDropped, labels...)
+		ch <- prometheus.MustNewConstMetric(c.rxPacketTotal, prometheus.GaugeValue, m.RxPacketTotal, labels...)
+		ch <- prometheus.MustNewConstMetric(c.rxPacketDropped, prometheus.GaugeValue, m.RxPacketDropped, labels...)
+		ch <- prometheus.MustNewConstMetric(c.txByteTotal, prometheus.GaugeValue, m.TxByteTotal, labels...)
+		ch <- prometheus.MustNewConstMetric(c.txByteDropped, prometheus.GaugeValue, m.TxByteDropped, labels...)
+		ch <- prometheus.MustNewConstMetric(c.txPacketTotal, prometheus.GaugeValue, m.TxPacketTotal, labels...)
+		ch <- prometheus.MustNewConstMetric(c.txPacketDropped, prometheus.GaugeValue, m.TxPacketDropped, labels...)
+	}
+	return nil
+}
+
+func (c *policySegmentCollector) generatePolicySegmentStateMetrics(ctx context.Context, segments []policy.Segment) (stateMetrics []policySegmentStateMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "policy_segment", len(segments), func(i int) {
+		segment := segments[i]
+		state, err := c.policySegmentClient.GetSegmentState(ctx, segment.Id)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "Unable to get segment state", "path", segment.Path, "err", err)
+			return
+		}
+		stateMetric := policySegmentStateMetric{
+			Path:        segment.Path,
+			Name:        segment.DisplayName,
+			StateDetail: policyStateDetail(state.State),
+		}
+		mtx.Lock()
+		stateMetrics = append(stateMetrics, stateMetric)
+		mtx.Unlock()
+	})
+	return
+}
+
+func (c *policySegmentCollector) generatePolicySegmentPortMetrics(ctx context.Context, segments []policy.Segment) (portMetrics []policySegmentPortMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "policy_segment", len(segments), func(i int) {
+		segment := segments[i]
+		ports, err := c.policySegmentClient.GetSegmentPortCount(ctx, segment.Id)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "Unable to get segment ports", "path", segment.Path, "err", err)
+			return
+		}
+		portMetric := policySegmentPortMetric{
+			Path:  segment.Path,
+			Name:  segment.DisplayName,
+			Ports: float64(ports),
+		}
+		mtx.Lock()
+		portMetrics = append(portMetrics, portMetric)
+		mtx.Unlock()
+	})
+	return
+}
+
+func (c *policySegmentCollector) generatePolicySegmentStatisticMetrics(ctx context.Context, segments []policy.Segment) (statisticMetrics []policySegmentStatisticMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "policy_segment", len(segments), func(i int) {
+		segment := segments[i]
+		statistics, err := c.policySegmentClient.GetSegmentStatistics(ctx, segment.Id)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "Unable to get segment statistic", "path", segment.Path, "err", err)
+			return
+		}
+		statisticMetric := policySegmentStatisticMetric{
+			Path: segment.Path,
+			Name: segment.DisplayName,
+		}
+		for _, statistic := range statistics {
+			statisticMetric.RxByteTotal += dataCounterTotal(statistic.RxBytes)
+			statisticMetric.RxByteDropped += dataCounterDropped(statistic.RxBytes)
+			statisticMetric.RxPacketTotal += dataCounterTotal(statistic.RxPackets)
+			statisticMetric.RxPacketDropped += dataCounterDropped(statistic.RxPackets)
+			statisticMetric.TxByteTotal += dataCounterTotal(statistic.TxBytes)
+			statisticMetric.TxByteDropped += dataCounterDropped(statistic.TxBytes)
+			statisticMetric.TxPacketTotal += dataCounterTotal(statistic.TxPackets)
+			statisticMetric.TxPacketDropped += dataCounterDropped(statistic.TxPackets)
+		}
+		mtx.Lock()
+		statisticMetrics = append(statisticMetrics, statisticMetric)
+		mtx.Unlock()
+	})
+	return
+}
+
+// policySegmentAdminStateDetail returns the admin state of a segment, which is
+// UP when not set.
+func policySegmentAdminStateDetail(adminState string) map[string]float64 {
+	if adminState == "" {
+		adminState = "UP"
+	}
+	adminStateDetail := make(map[string]float64)
+	for _, possibleAdminState := range policySegmentPossibleAdminState {
+		adminStateValue := 0.0
+		if possibleAdminState == strings.ToUpper(adminState) {
+			adminStateValue = 1.0
+		}
+		adminStateDetail[possibleAdminState] = adminStateValue
+	}
+	return adminStateDetail
+}
+
+func dataCounterTotal(counter *manager.DataCounter) float64 {
+	if counter == nil {
+		return 0
+	}
+	return float64(counter.Total)
+}
+
+func dataCounterDropped(counter *manager.DataCounter) float64 {
+	if counter == nil {
+		return 0
+	}
+	return float64(counter.Dropped)
+}
@@ -0,0 +1,240 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"nsxt_exporter/client/policy"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/go-vmware-nsxt/manager"
+)
+
+type mockPolicySegmentClient struct {
+	responses map[string]mockPolicySegmentResponse
+}
+
+type mockPolicySegmentResponse struct {
+	State      string
+	StateError error
+	Ports      int64
+	PortsError error
+	Statistics []policy.SegmentStatistics
+	StatsError error
+}
+
+func (c *mockPolicySegmentClient) ListAllSegments(ctx context.Context) ([]policy.Segment, error) {
+	panic("unused function. Only used to satisfy PolicySegmentClient interface")
+}
+
+func (c *mockPolicySegmentClient) GetSegmentState(ctx context.Context, segmentID string) (manager.ConfigurationState, error) {
+	res, ok := c.responses[segmentID]
+	if !ok {
+		return manager.ConfigurationState{}, errors.New("error segment not found")
+	}
+	return manager.ConfigurationState{State: res.State}, res.StateError
+}
+
+func (c *mockPolicySegmentClient) GetSegmentPortCount(ctx context.Context, segmentID string) (int64, error) {
+	res, ok := c.responses[segmentID]
+	if !ok {
+		return 0, errors.New("error segment not found")
+	}
+	return res.Ports, res.PortsError
+}
+
+func (c *mockPolicySegmentClient) GetSegmentStatistics(ctx context.Context, segmentID string) ([]policy.SegmentStatistics, error) {
+	res, ok := c.responses[segmentID]
+	if !ok {
+		return nil, errors.New("error segment not found")
+	}
+	return res.Statistics, res.StatsError
+}
+
+func buildPolicySegmentStatistics(enforcementPointPath string, value int64) policy.SegmentStatistics {
+	return policy.SegmentStatistics{
+		LogicalSwitchStatistics: manager.LogicalSwitchStatistics{
+			RxBytes:   &manager.DataCounter{Total: value, Dropped: value + 1},
+			RxPackets: &manager.DataCounter{Total: value + 2, Dropped: value + 3},
+			TxBytes:   &manager.DataCounter{Total: value + 4, Dropped: value + 5},
+			TxPackets: &manager.DataCounter{Total: value + 6, Dropped: value + 7},
+		},
+		EnforcementPointPath: enforcementPointPath,
+	}
+}
+
+func buildPolicySegments() []policy.Segment {
+	return []policy.Segment{
+		{Id: "seg-a", Path: "/infra/segments/seg-a", DisplayName: "seg-a-name"},
+		{Id: "seg-b", Path: "/infra/segments/seg-b", DisplayName: "seg-b-name"},
+	}
+}
+
+func TestPolicySegmentCollector_GeneratePolicySegmentStateMetrics(t *testing.T) {
+	testcases := []struct {
+		description     string
+		responses       map[string]mockPolicySegmentResponse
+		expectedMetrics []policySegmentStateMetric
+	}{
+		{
+			description: "Should return state metrics of every segment",
+			responses: map[string]mockPolicySegmentResponse{
+				"seg-a": {State: "success"},
+				"seg-b": {State: "FAILED"},
+			},
+			expectedMetrics: []policySegmentStateMetric{
+				{Path: "/infra/segments/seg-a", Name: "seg-a-name", StateDetail: buildExpectedPolicyStateDetail("success")},
+				{Path: "/infra/segments/seg-b", Name: "seg-b-name", StateDetail: buildExpectedPolicyStateDetail("failed")},
+			},
+		},
+		{
+			description: "Should only return state metrics with valid response",
+			responses: map[string]mockPolicySegmentResponse{
+				"seg-a": {State: "success", StateError: errors.New("error get segment state")},
+				"seg-b": {State: "in_progress"},
+			},
+			expectedMetrics: []policySegmentStateMetric{
+				{Path: "/infra/segments/seg-b", Name: "seg-b-name", StateDetail: buildExpectedPolicyStateDetail("in_progress")},
+			},
+		},
+		{
+			description:     "Should return empty metrics when empty response",
+			responses:       map[string]mockPolicySegmentResponse{},
+			expectedMetrics: []policySegmentStateMetric{},
+		},
+	}
+	for _, tc := range testcases {
+		mockPolicySegmentClient := &mockPolicySegmentClient{
+			responses: tc.responses,
+		}
+		segmentCollector := newPolicySegmentCollector(mockPolicySegmentClient, log.NewNopLogger())
+		stateMetrics := segmentCollector.generatePolicySegmentStateMetrics(context.Background(), buildPolicySegments())
+		assert.ElementsMatch(t, tc.expectedMetrics, stateMetrics, tc.description)
+	}
+}
+
+func TestPolicySegmentCollector_GeneratePolicySegmentPortMetrics(t *testing.T) {
+	testcases := []struct {
+		description     string
+		responses       map[string]mockPolicySegmentResponse
+		expectedMetrics []policySegmentPortMetric
+	}{
+		{
+			description: "Should return port metrics of every segment",
+			responses: map[string]mockPolicySegmentResponse{
+				"seg-a": {Ports: 3},
+				"seg-b": {Ports: 0},
+			},
+			expectedMetrics: []policySegmentPortMetric{
+				{Path: "/infra/segments/seg-a", Name: "seg-a-name", Ports: 3},
+				{Path: "/infra/segments/seg-b", Name: "seg-b-name", Ports: 0},
+			},
+		},
+		{
+			description: "Should only return port metrics with valid response",
+			responses: map[string]mockPolicySegmentResponse{
+				"seg-a": {Ports: 3, PortsError: errors.New("error get segment ports")},
+				"seg-b": {Ports: 5},
+			},
+			expectedMetrics: []policySegmentPortMetric{
+				{Path: "/infra/segments/seg-b", Name: "seg-b-name", Ports: 5},
+			},
+		},
+	}
+	for _, tc := range testcases {
+		mockPolicySegmentClient := &mockPolicySegmentClient{
+			responses: tc.responses,
+		}
+		segmentCollector := newPolicySegmentCollector(mockPolicySegmentClient, log.NewNopLogger())
+		portMetrics := segmentCollector.generatePolicySegmentPortMetrics(context.Background(), buildPolicySegments())
+		assert.ElementsMatch(t, tc.expectedMetrics, portMetrics, tc.description)
+	}
+}
+
+func TestPolicySegmentCollector_GeneratePolicySegmentStatisticMetrics(t *testing.T) {
+	testcases := []struct {
+		description     string
+		responses       map[string]mockPolicySegmentResponse
+		expectedMetrics []policySegmentStatisticMetric
+	}{
+		{
+			description: "Should aggregate statistics across enforcement points",
+			responses: map[string]mockPolicySegmentResponse{
+				"seg-a": {Statistics: []policy.SegmentStatistics{
+					buildPolicySegmentStatistics("/infra/sites/default/enforcement-points/default", 10),
+					buildPolicySegmentStatistics("/infra/sites/dr/enforcement-points/default", 100),
+				}},
+				"seg-b": {Statistics: []policy.SegmentStatistics{
+					buildPolicySegmentStatistics("/infra/sites/default/enforcement-points/default", 1),
+				}},
+			},
+			expectedMetrics: []policySegmentStatisticMetric{
+				{
+					Path:            "/infra/segments/seg-a",
+					Name:            "seg-a-name",
+					RxByteTotal:     110,
+					RxByteDropped:   112,
+					RxPacketTotal:   114,
+					RxPacketDropped: 116,
+					TxByteTotal:     118,
+					TxByteDropped:   120,
+					TxPacketTotal:   122,
+					TxPacketDropped: 124,
+				},
+				{
+					Path:            "/infra/segments/seg-b",
+					Name:            "seg-b-name",
+					RxByteTotal:     1,
+					RxByteDropped:   2,
+					RxPacketTotal:   3,
+					RxPacketDropped: 4,
+					TxByteTotal:     5,
+					TxByteDropped:   6,
+					TxPacketTotal:   7,
+					TxPacketDropped: 8,
+				},
+			},
+		},
+		{
+			description: "Should return zero statistics when counters are missing",
+			responses: map[string]mockPolicySegmentResponse{
+				"seg-a": {Statistics: []policy.SegmentStatistics{{EnforcementPointPath: "/infra/sites/default/enforcement-points/default"}}},
+				"seg-b": {StatsError: errors.New("error get segment statistics")},
+			},
+			expectedMetrics: []policySegmentStatisticMetric{
+				{Path: "/infra/segments/seg-a", Name: "seg-a-name"},
+			},
+		},
+	}
+	for _, tc := range testcases {
+		mockPolicySegmentClient := &mockPolicySegmentClient{
+			responses: tc.responses,
+		}
+		segmentCollector := newPolicySegmentCollector(mockPolicySegmentClient, log.NewNopLogger())
+		statisticMetrics := segmentCollector.generatePolicySegmentStatisticMetrics(context.Background(), buildPolicySegments())
+		assert.ElementsMatch(t, tc.expectedMetrics, statisticMetrics, tc.description)
+	}
+}
+
+func TestPolicySegmentCollector_PolicySegmentAdminStateDetail(t *testing.T) {
+	testcases := []struct {
+		description    string
+		adminState     string
+		expectedDetail map[string]float64
+	}{
+		{
+			description:    "Should return admin state",
+			adminState:     "DOWN",
+			expectedDetail: map[string]float64{"UP": 0.0, "DOWN": 1.0},
+		},
+		{
+			description:    "Should return UP when admin state is not set",
+			adminState:     "",
+			expectedDetail: map[string]float64{"UP": 1.0, "DOWN": 0.0},
+		},
+	}
+	for _, tc := range testcases {
+		assert.Equal(t, tc.expectedDetail, policySegmentAdminStateDetail(tc.adminState), tc.description)
+	}
+}
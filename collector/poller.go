@@ -0,0 +1,167 @@
+package collector
+
+import (
+	"context"
+	"nsxt_exporter/client"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var lastSuccessDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "collector", "last_success_timestamp_seconds"),
+	"nsxt_exporter: Timestamp of the last successful background run of a collector.",
+	[]string{"collector"},
+	nil,
+)
+
+// Poller runs the collectors with a poll interval in the background and keeps
+// the metrics of their last run, so that scrapes don't query the NSX-T manager
+// for them.
+type Poller struct {
+	snapshots map[string]*snapshot
+	logger    log.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// snapshot holds the metrics of the last run of a polled collector.
+type snapshot struct {
+	collector Collector
+
+	mtx         sync.RWMutex
+	metrics     []prometheus.Metric
+	lastSuccess time.Time
+}
+
+// NewPoller starts polling the given collectors, or the collectors enabled by
+// flags if none are given, that have a poll interval. Collectors without a
+// poll interval are left to run on every scrape. Until their first run
+// completes, collectors serve the last metrics of previous, which may be nil,
+// e.g. the poller replaced on reload.
+func NewPoller(apiClient *client.APIClient, previous *Poller, logger log.Logger, filters ...string) (*Poller, error) {
+	names, err := selectCollectors(filters...)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Poller{
+		snapshots: make(map[string]*snapshot),
+		logger:    logger,
+		cancel:    cancel,
+	}
+	for _, name := range names {
+		interval := pollInterval(name)
+		if interval <= 0 {
+			continue
+		}
+		level.Info(logger).Log("msg", "Polling collector in the background", "collector", name, "interval", interval)
+		s := &snapshot{
+			collector: factories[name](apiClient, log.With(logger, "collector", name)),
+		}
+		if previous.polls(name) {
+			s.carryOver(previous.snapshots[name])
+		}
+		p.snapshots[name] = s
+		p.wg.Add(1)
+		go func(name string, interval time.Duration) {
+			defer p.wg.Done()
+			p.poll(ctx, name, s, interval)
+		}(name, interval)
+	}
+	return p, nil
+}
+
+// Stop stops polling and waits for running collectors to return.
+func (p *Poller) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *Poller) poll(ctx context.Context, name string, s *snapshot, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		p.update(ctx, name, s, interval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// update runs the collector once, giving it at most interval to complete, and
+// replaces the metrics of the snapshot with the result.
+func (p *Poller) update(ctx context.Context, name string, s *snapshot, interval time.Duration) {
+	updateCtx, cancel := context.WithTimeout(ctx, interval)
+	defer cancel()
+
+	ch := make(chan prometheus.Metric)
+	done := make(chan []prometheus.Metric)
+	go func() {
+		var metrics []prometheus.Metric
+		for m := range ch {
+			metrics = append(metrics, m)
+		}
+		done <- metrics
+	}()
+	success := execute(updateCtx, name, s.collector, ch, p.logger)
+	close(ch)
+	metrics := <-done
+
+	if ctx.Err() != nil {
+		// The poller was stopped, the metrics are incomplete.
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.metrics = metrics
+	if success {
+		s.lastSuccess = time.Now()
+	}
+}
+
+// carryOver copies the metrics of the last run from previous.
+func (s *snapshot) carryOver(previous *snapshot) {
+	previous.mtx.RLock()
+	defer previous.mtx.RUnlock()
+	s.metrics = previous.metrics
+	s.lastSuccess = previous.lastSuccess
+}
+
+// polls returns whether the collector with the given name is polled. A nil
+// Poller polls no collector.
+func (p *Poller) polls(name string) bool {
+	if p == nil {
+		return false
+	}
+	_, ok := p.snapshots[name]
+	return ok
+}
+
+func (p *Poller) describe(ch chan<- *prometheus.Desc, names ...string) {
+	ch <- lastSuccessDesc
+	for _, name := range names {
+		p.snapshots[name].collector.Describe(ch)
+	}
+}
+
+// collect sends the metrics of the last run of the given collectors.
+func (p *Poller) collect(ch chan<- prometheus.Metric, names ...string) {
+	for _, name := range names {
+		s := p.snapshots[name]
+		s.mtx.RLock()
+		for _, m := range s.metrics {
+			ch <- m
+		}
+		if !s.lastSuccess.IsZero() {
+			ch <- prometheus.MustNewConstMetric(lastSuccessDesc, prometheus.GaugeValue, float64(s.lastSuccess.UnixNano())/1e9, name)
+		}
+		s.mtx.RUnlock()
+	}
+}
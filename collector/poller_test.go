@@ -0,0 +1,134 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+var fakePolledDesc = prometheus.NewDesc("nsxt_fake_polled", "Fake metric of a polled collector", nil, nil)
+
+type mockPolledCollector struct {
+	err error
+}
+
+func (c *mockPolledCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- fakePolledDesc
+}
+
+func (c *mockPolledCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	ch <- prometheus.MustNewConstMetric(fakePolledDesc, prometheus.GaugeValue, 1)
+	return c.err
+}
+
+func collectPolledMetrics(p *Poller, names ...string) map[string]float64 {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		p.collect(ch, names...)
+		close(ch)
+	}()
+	values := make(map[string]float64)
+	for m := range ch {
+		var metric dto.Metric
+		m.Write(&metric)
+		values[m.Desc().String()] = metric.GetGauge().GetValue()
+	}
+	return values
+}
+
+func TestPoller_Update(t *testing.T) {
+	lastSuccess := time.Unix(1600000000, 0)
+	testcases := []struct {
+		description         string
+		err                 error
+		lastSuccess         time.Time
+		expectedMetrics     []*prometheus.Desc
+		expectedLastSuccess func(time.Time) bool
+	}{
+		{
+			description: "Should keep metrics and update last success when collector succeeds",
+			err:         nil,
+			lastSuccess: lastSuccess,
+			expectedMetrics: []*prometheus.Desc{
+				fakePolledDesc, scrapeDurationDesc, scrapeSuccessDesc, scrapeTimeoutDesc, lastSuccessDesc,
+			},
+			expectedLastSuccess: func(t time.Time) bool { return t.After(lastSuccess) },
+		},
+		{
+			description: "Should keep metrics and last success when collector fails",
+			err:         errors.New("error list objects"),
+			lastSuccess: lastSuccess,
+			expectedMetrics: []*prometheus.Desc{
+				fakePolledDesc, scrapeDurationDesc, scrapeSuccessDesc, scrapeTimeoutDesc, lastSuccessDesc,
+			},
+			expectedLastSuccess: func(t time.Time) bool { return t.Equal(lastSuccess) },
+		},
+		{
+			description: "Should not report last success when collector never succeeded",
+			err:         errors.New("error list objects"),
+			lastSuccess: time.Time{},
+			expectedMetrics: []*prometheus.Desc{
+				fakePolledDesc, scrapeDurationDesc, scrapeSuccessDesc, scrapeTimeoutDesc,
+			},
+			expectedLastSuccess: func(t time.Time) bool { return t.IsZero() },
+		},
+	}
+	for _, tc := range testcases {
+		s := &snapshot{
+			collector:   &mockPolledCollector{err: tc.err},
+			lastSuccess: tc.lastSuccess,
+		}
+		p := &Poller{
+			snapshots: map[string]*snapshot{"fake": s},
+			logger:    log.NewNopLogger(),
+		}
+		p.update(context.Background(), "fake", s, time.Minute)
+		assert.True(t, tc.expectedLastSuccess(s.lastSuccess), tc.description)
+
+		metrics := collectPolledMetrics(p, "fake")
+		var descs []string
+		for desc := range metrics {
+			descs = append(descs, desc)
+		}
+		var expectedDescs []string
+		for _, desc := range tc.expectedMetrics {
+			expectedDescs = append(expectedDescs, desc.String())
+		}
+		assert.ElementsMatch(t, expectedDescs, descs, tc.description)
+	}
+}
+
+func TestPoller_Polls(t *testing.T) {
+	var nilPoller *Poller
+	assert.False(t, nilPoller.polls("fake"), "Should not poll any collector when poller is nil")
+	p := &Poller{
+		snapshots: map[string]*snapshot{"fake": {}},
+	}
+	assert.True(t, p.polls("fake"), "Should poll collector with snapshot")
+	assert.False(t, p.polls("other"), "Should not poll collector without snapshot")
+}
+
+func TestSnapshot_CarryOver(t *testing.T) {
+	lastSuccess := time.Unix(1600000000, 0)
+	previous := &snapshot{
+		collector:   &mockPolledCollector{},
+		metrics:     []prometheus.Metric{prometheus.MustNewConstMetric(fakePolledDesc, prometheus.GaugeValue, 1)},
+		lastSuccess: lastSuccess,
+	}
+	s := &snapshot{collector: &mockPolledCollector{}}
+	s.carryOver(previous)
+	p := &Poller{
+		snapshots: map[string]*snapshot{"fake": s},
+	}
+	metrics := collectPolledMetrics(p, "fake")
+	assert.Equal(t, map[string]float64{
+		fakePolledDesc.String():  1,
+		lastSuccessDesc.String(): float64(lastSuccess.Unix()),
+	}, metrics, "Should serve metrics and last success of previous snapshot")
+}
@@ -0,0 +1,44 @@
+package collector
+
+import (
+	"context"
+	"nsxt_exporter/client"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/vmware/go-vmware-nsxt/manager"
+)
+
+// routerNode is a transport node running the service router of a logical
+// router.
+type routerNode struct {
+	logicalRouter   manager.LogicalRouter
+	transportNodeID string
+}
+
+// listRouterNodes returns the transport nodes running the service router of
+// every logical router, querying the router status within the concurrency
+// limit of the given collector. Routers without an edge cluster have no
+// service router and are skipped.
+func listRouterNodes(ctx context.Context, collector string, logicalRouterClient client.LogicalRouterClient, logicalRouters []manager.LogicalRouter, logger log.Logger) []routerNode {
+	var mtx sync.Mutex
+	var routerNodes []routerNode
+	forEach(ctx, collector, len(logicalRouters), func(i int) {
+		logicalRouter := logicalRouters[i]
+		if logicalRouter.EdgeClusterId == "" {
+			return
+		}
+		lrouterStatus, err := logicalRouterClient.GetLogicalRouterStatus(ctx, logicalRouter.Id)
+		if err != nil {
+			level.Error(logger).Log("msg", "Unable to get logical router status", "id", logicalRouter.Id, "err", err)
+			return
+		}
+		mtx.Lock()
+		for _, status := range lrouterStatus.PerNodeStatus {
+			routerNodes = append(routerNodes, routerNode{logicalRouter: logicalRouter, transportNodeID: status.TransportNodeId})
+		}
+		mtx.Unlock()
+	})
+	return routerNodes
+}
@@ -0,0 +1,159 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"nsxt_exporter/client"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vmware/go-vmware-nsxt/manager"
+)
+
+var (
+	routingTablePossibleRouteType = [...]string{"connected", "static", "bgp", "nat", "other"}
+
+	// routeTypes maps the route types reported by NSX-T, which differ between
+	// the routing and forwarding table and between Tier-0 and Tier-1 routers,
+	// to the route types reported by the exporter.
+	routeTypes = map[string]string{
+		"c":         "connected",
+		"connected": "connected",
+		"t0c":       "connected",
+		"t1c":       "connected",
+		"s":         "static",
+		"static":    "static",
+		"user":      "static",
+		"t0s":       "static",
+		"t1s":       "static",
+		"b":         "bgp",
+		"bgp":       "bgp",
+		"ebgp":      "bgp",
+		"ibgp":      "bgp",
+		"nat":       "nat",
+		"t0n":       "nat",
+		"t1n":       "nat",
+	}
+)
+
+func init() {
+	registerCollector("routing_table", defaultDisabled, createRoutingTableCollectorFactory)
+}
+
+type routingTableCollector struct {
+	logicalRouterClient client.LogicalRouterClient
+	logger              log.Logger
+
+	routingTableRoutes    *prometheus.Desc
+	forwardingTableRoutes *prometheus.Desc
+}
+
+type routingTableMetric struct {
+	LogicalRouterID       string
+	LogicalRouterName     string
+	RouterType            string
+	TransportNodeID       string
+	RoutingTableRoutes    map[string]float64
+	ForwardingTableRoutes map[string]float64
+}
+
+func createRoutingTableCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
+	nsxtClient := client.NewNSXTClient(apiClient, logger)
+	return newRoutingTableCollector(nsxtClient, logger)
+}
+
+func newRoutingTableCollector(logicalRouterClient client.LogicalRouterClient, logger log.Logger) *routingTableCollector {
+	routingTableRoutes := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "logical_router", "routing_table_routes"),
+		"Number of routes in routing table of logical router service router on transport node",
+		[]string{"logical_router_id", "logical_router_name", "router_type", "transport_node_id", "route_type"},
+		nil,
+	)
+	forwardingTableRoutes := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "logical_router", "forwarding_table_routes"),
+		"Number of routes in forwarding table of logical router service router on transport node",
+		[]string{"logical_router_id", "logical_router_name", "router_type", "transport_node_id", "route_type"},
+		nil,
+	)
+	return &routingTableCollector{
+		logicalRouterClient:   logicalRouterClient,
+		logger:                logger,
+		routingTableRoutes:    routingTableRoutes,
+		forwardingTableRoutes: forwardingTableRoutes,
+	}
+}
+
+// Describe implements the Collector interface.
+func (c *routingTableCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.routingTableRoutes
+	ch <- c.forwardingTableRoutes
+}
+
+// Update implements the Collector interface.
+func (c *routingTableCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	logicalRouters, err := c.logicalRouterClient.ListAllLogicalRouters(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list logical routers: %s", err)
+	}
+	routingTableMetrics := c.generateRoutingTableMetrics(ctx, logicalRouters)
+	for _, m := range routingTableMetrics {
+		for routeType, value := range m.RoutingTableRoutes {
+			ch <- prometheus.MustNewConstMetric(c.routingTableRoutes, prometheus.GaugeValue, value, m.LogicalRouterID, m.LogicalRouterName, m.RouterType, m.TransportNodeID, routeType)
+		}
+		for routeType, value := range m.ForwardingTableRoutes {
+			ch <- prometheus.MustNewConstMetric(c.forwardingTableRoutes, prometheus.GaugeValue, value, m.LogicalRouterID, m.LogicalRouterName, m.RouterType, m.TransportNodeID, routeType)
+		}
+	}
+	return nil
+}
+
+// generateRoutingTableMetrics returns the size of the routing and forwarding
+// table of every logical router on the transport nodes running its service
+// router.
+func (c *routingTableCollector) generateRoutingTableMetrics(ctx context.Context, logicalRouters []manager.LogicalRouter) (routingTableMetrics []routingTableMetric) {
+	routerNodes := listRouterNodes(ctx, "routing_table", c.logicalRouterClient, logicalRouters, c.logger)
+	var mtx sync.Mutex
+	forEach(ctx, "routing_table", len(routerNodes), func(i int) {
+		logicalRouter, transportNodeID := routerNodes[i].logicalRouter, routerNodes[i].transportNodeID
+		routingTable, err := c.logicalRouterClient.ListAllRoutingTableRoutes(ctx, logicalRouter.Id, transportNodeID)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "Unable to get routing table", "id", logicalRouter.Id, "transportNodeID", transportNodeID, "err", err)
+			return
+		}
+		forwardingTable, err := c.logicalRouterClient.ListAllForwardingTableRoutes(ctx, logicalRouter.Id, transportNodeID)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "Unable to get forwarding table", "id", logicalRouter.Id, "transportNodeID", transportNodeID, "err", err)
+			return
+		}
+		routingTableMetric := routingTableMetric{
+			LogicalRouterID:       logicalRouter.Id,
+			LogicalRouterName:     logicalRouter.DisplayName,
+			RouterType:            logicalRouter.RouterType,
+			TransportNodeID:       transportNodeID,
+			RoutingTableRoutes:    countRoutesByType(routingTable),
+			ForwardingTableRoutes: countRoutesByType(forwardingTable),
+		}
+		mtx.Lock()
+		routingTableMetrics = append(routingTableMetrics, routingTableMetric)
+		mtx.Unlock()
+	})
+	return
+}
+
+func countRoutesByType(routes []manager.LogicalRouterRouteEntry) map[string]float64 {
+	routeCount := make(map[string]float64)
+	for _, routeType := range routingTablePossibleRouteType {
+		routeCount[routeType] = 0.0
+	}
+	for _, route := range routes {
+		routeType, ok := routeTypes[strings.ToLower(route.RouteType)]
+		if !ok {
+			routeType = "other"
+		}
+		routeCount[routeType]++
+	}
+	return routeCount
+}
@@ -0,0 +1,161 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"nsxt_exporter/client/routing"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/go-vmware-nsxt/manager"
+)
+
+const (
+	fakeRoutingTableLogicalRouterID = "fake-logical-router-id"
+	fakeRoutingTableEdgeClusterID   = "fake-edge-cluster-id"
+)
+
+type mockRoutingTableLogicalRouterClient struct {
+	transportNodes  []string
+	routerStatusErr error
+	responses       map[string]mockRoutingTableResponse
+}
+
+type mockRoutingTableResponse struct {
+	RoutingTable         []manager.LogicalRouterRouteEntry
+	RoutingTableError    error
+	ForwardingTable      []manager.LogicalRouterRouteEntry
+	ForwardingTableError error
+}
+
+func (c *mockRoutingTableLogicalRouterClient) ListAllLogicalRouters(ctx context.Context) ([]manager.LogicalRouter, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockRoutingTableLogicalRouterClient) GetLogicalRouterStatus(ctx context.Context, lrouterID string) (manager.LogicalRouterStatus, error) {
+	if c.routerStatusErr != nil {
+		return manager.LogicalRouterStatus{}, c.routerStatusErr
+	}
+	var perNodeStatus []manager.LogicalRouterStatusPerNode
+	for _, transportNodeID := range c.transportNodes {
+		perNodeStatus = append(perNodeStatus, manager.LogicalRouterStatusPerNode{TransportNodeId: transportNodeID})
+	}
+	return manager.LogicalRouterStatus{LogicalRouterId: lrouterID, PerNodeStatus: perNodeStatus}, nil
+}
+
+func (c *mockRoutingTableLogicalRouterClient) ListAllNatRules(ctx context.Context, lrouterID string) ([]manager.NatRule, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockRoutingTableLogicalRouterClient) GetNatStatisticsPerRule(ctx context.Context, lrouterID, ruleID string) (manager.NatStatisticsPerRule, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockRoutingTableLogicalRouterClient) ListAllBgpNeighborsStatus(ctx context.Context, lrouterID string) ([]manager.BgpNeighborStatus, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockRoutingTableLogicalRouterClient) ListAllBfdPeersStatus(ctx context.Context, lrouterID, transportNodeID string) ([]routing.BfdPeerStatus, error) {
+	panic("unused function. Only used to satisfy LogicalRouterClient interface")
+}
+
+func (c *mockRoutingTableLogicalRouterClient) ListAllRoutingTableRoutes(ctx context.Context, lrouterID, transportNodeID string) ([]manager.LogicalRouterRouteEntry, error) {
+	res, ok := c.responses[transportNodeID]
+	if !ok {
+		return nil, errors.New("error transport node not found")
+	}
+	return res.RoutingTable, res.RoutingTableError
+}
+
+func (c *mockRoutingTableLogicalRouterClient) ListAllForwardingTableRoutes(ctx context.Context, lrouterID, transportNodeID string) ([]manager.LogicalRouterRouteEntry, error) {
+	res, ok := c.responses[transportNodeID]
+	if !ok {
+		return nil, errors.New("error transport node not found")
+	}
+	return res.ForwardingTable, res.ForwardingTableError
+}
+
+func buildRoutes(routeTypes ...string) []manager.LogicalRouterRouteEntry {
+	var routes []manager.LogicalRouterRouteEntry
+	for _, routeType := range routeTypes {
+		routes = append(routes, manager.LogicalRouterRouteEntry{RouteType: routeType})
+	}
+	return routes
+}
+
+func buildExpectedRouteCount(connected, static, bgp, nat, other float64) map[string]float64 {
+	return map[string]float64{
+		"connected": connected,
+		"static":    static,
+		"bgp":       bgp,
+		"nat":       nat,
+		"other":     other,
+	}
+}
+
+func TestRoutingTableCollector_GenerateRoutingTableMetrics(t *testing.T) {
+	logicalRouters := []manager.LogicalRouter{
+		{Id: fakeRoutingTableLogicalRouterID, DisplayName: fakeRoutingTableLogicalRouterID + "-name", RouterType: "TIER0", EdgeClusterId: fakeRoutingTableEdgeClusterID},
+		{Id: "fake-distributed-router-id", DisplayName: "fake-distributed-router-name", RouterType: "TIER1"},
+	}
+	testcases := []struct {
+		description     string
+		routerStatusErr error
+		responses       map[string]mockRoutingTableResponse
+		expectedMetrics []routingTableMetric
+	}{
+		{
+			description: "Should return route counts by route type of every transport node",
+			responses: map[string]mockRoutingTableResponse{
+				"edge-1": {
+					RoutingTable:    buildRoutes("t0c", "t0c", "t0s", "b", "b", "b", "t0n", "t1c", "NSX_INTERNAL"),
+					ForwardingTable: buildRoutes("CONNECTED", "USER", "BGP", "bgp"),
+				},
+				"edge-2": {},
+			},
+			expectedMetrics: []routingTableMetric{
+				{
+					LogicalRouterID:       fakeRoutingTableLogicalRouterID,
+					LogicalRouterName:     fakeRoutingTableLogicalRouterID + "-name",
+					RouterType:            "TIER0",
+					TransportNodeID:       "edge-1",
+					RoutingTableRoutes:    buildExpectedRouteCount(3, 1, 3, 1, 1),
+					ForwardingTableRoutes: buildExpectedRouteCount(1, 1, 2, 0, 0),
+				},
+				{
+					LogicalRouterID:       fakeRoutingTableLogicalRouterID,
+					LogicalRouterName:     fakeRoutingTableLogicalRouterID + "-name",
+					RouterType:            "TIER0",
+					TransportNodeID:       "edge-2",
+					RoutingTableRoutes:    buildExpectedRouteCount(0, 0, 0, 0, 0),
+					ForwardingTableRoutes: buildExpectedRouteCount(0, 0, 0, 0, 0),
+				},
+			},
+		},
+		{
+			description: "Should only return route counts with valid response",
+			responses: map[string]mockRoutingTableResponse{
+				"edge-1": {RoutingTableError: errors.New("error get routing table")},
+				"edge-2": {ForwardingTableError: errors.New("error get forwarding table")},
+			},
+			expectedMetrics: []routingTableMetric{},
+		},
+		{
+			description:     "Should return empty metrics when logical router status fails",
+			routerStatusErr: errors.New("error get logical router status"),
+			responses:       map[string]mockRoutingTableResponse{},
+			expectedMetrics: []routingTableMetric{},
+		},
+	}
+	for _, tc := range testcases {
+		mockRoutingTableLogicalRouterClient := &mockRoutingTableLogicalRouterClient{
+			transportNodes:  []string{"edge-1", "edge-2"},
+			routerStatusErr: tc.routerStatusErr,
+			responses:       tc.responses,
+		}
+		routingTableCollector := newRoutingTableCollector(mockRoutingTableLogicalRouterClient, log.NewNopLogger())
+		routingTableMetrics := routingTableCollector.generateRoutingTableMetrics(context.Background(), logicalRouters)
+		assert.ElementsMatch(t, tc.expectedMetrics, routingTableMetrics, tc.description)
+	}
+}
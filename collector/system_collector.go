@@ -1,13 +1,14 @@
 package collector
 
 import (
+	"context"
+	"fmt"
 	"nsxt_exporter/client"
 	"strings"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
-	nsxt "github.com/vmware/go-vmware-nsxt"
 	"github.com/vmware/go-vmware-nsxt/administration"
 )
 
@@ -15,7 +16,7 @@ var possibleSystemServiceStatus = [...]string{"RUNNING", "STOPPED"}
 var possibleNodeStatus = [...]string{"CONNECTED", "DISCONNECTED", "UNKNOWN"}
 
 func init() {
-	registerCollector("system", createSystemCollectorFactory)
+	registerCollector("system", defaultEnabled, createSystemCollectorFactory)
 }
 
 type systemCollector struct {
@@ -68,7 +69,7 @@ type serviceStatusMetric struct {
 	StatusDetail map[string]float64
 }
 
-func createSystemCollectorFactory(apiClient *nsxt.APIClient, logger log.Logger) prometheus.Collector {
+func createSystemCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
 	nsxtClient := client.NewNSXTClient(apiClient, logger)
 	return newSystemCollector(nsxtClient, logger)
 }
@@ -165,21 +166,37 @@ func newSystemCollector(systemClient client.SystemClient, logger log.Logger) *sy
 	}
 }
 
-// Describe implements the prometheus.Collector interface.
+// Describe implements the Collector interface.
 func (sc *systemCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- sc.clusterStatus
 	ch <- sc.clusterNodeStatus
+	ch <- sc.clusterNodeCPUCoresUse
+	ch <- sc.clusterNodeCPUCoresTotal
+	ch <- sc.clusterNodeMemoryUse
+	ch <- sc.clusterNodeMemoryTotal
+	ch <- sc.clusterNodeMemoryCached
+	ch <- sc.clusterNodeSwapUse
+	ch <- sc.clusterNodeSwapTotal
+	ch <- sc.clusterNodeDiskUse
+	ch <- sc.clusterNodeDiskTotal
 	ch <- sc.systemServiceStatus
 }
 
-// Collect implements the prometheus.Collector interface.
-func (sc *systemCollector) Collect(ch chan<- prometheus.Metric) {
-	clusterStatusMetrics := sc.collectClusterStatusMetrics()
+// Update implements the Collector interface.
+func (sc *systemCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	var errs []string
+	clusterStatusMetrics, err := sc.collectClusterStatusMetrics(ctx)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
 	for _, sm := range clusterStatusMetrics {
 		ch <- prometheus.MustNewConstMetric(sc.clusterStatus, prometheus.GaugeValue, sm.Status)
 	}
 
-	controllerNodeStatusMetrics, nodeMetrics := sc.collectClusterNodeMetrics()
+	controllerNodeStatusMetrics, nodeMetrics, err := sc.collectClusterNodeMetrics(ctx)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
 	for _, nm := range nodeMetrics {
 		nodeType := "management"
 		for status, value := range nm.StatusDetail {
@@ -209,20 +226,24 @@ func (sc *systemCollector) Collect(ch chan<- prometheus.Metric) {
 		}
 	}
 
-	serviceMetrics := sc.collectServiceStatusMetrics()
+	serviceMetrics := sc.collectServiceStatusMetrics(ctx)
 	for _, svm := range serviceMetrics {
 		for status, value := range svm.StatusDetail {
 			ch <- prometheus.MustNewConstMetric(sc.systemServiceStatus, prometheus.GaugeValue, value, svm.Name, status)
 		}
 	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
 }
 
-func (sc *systemCollector) collectClusterStatusMetrics() (clusterStatusMetrics []clusterStatusMetric) {
-	clusterStatus, err := sc.systemClient.ReadClusterStatus()
+func (sc *systemCollector) collectClusterStatusMetrics(ctx context.Context) ([]clusterStatusMetric, error) {
+	clusterStatus, err := sc.systemClient.ReadClusterStatus(ctx)
 	if err != nil {
-		level.Error(sc.logger).Log("msg", "Unable to collect cluster status")
-		return
+		return nil, fmt.Errorf("unable to collect cluster status: %s", err)
 	}
+	var clusterStatusMetrics []clusterStatusMetric
 	clusterStatusMetric := clusterStatusMetric{
 		Status: 0.0,
 	}
@@ -231,20 +252,19 @@ func (sc *systemCollector) collectClusterStatusMetrics() (clusterStatusMetrics [
 		clusterStatusMetric.Status = 1.0
 	}
 	clusterStatusMetrics = append(clusterStatusMetrics, clusterStatusMetric)
-	return
+	return clusterStatusMetrics, nil
 }
 
-func (sc *systemCollector) collectClusterNodeMetrics() (controllerNodeStatusMetrics []controllerNodeStatusMetric, managementNodeMetrics []managementNodeMetric) {
-	clusterNodes, err := sc.systemClient.ReadClusterNodesAggregateStatus()
+func (sc *systemCollector) collectClusterNodeMetrics(ctx context.Context) ([]controllerNodeStatusMetric, []managementNodeMetric, error) {
+	clusterNodes, err := sc.systemClient.ReadClusterNodesAggregateStatus(ctx)
 	if err != nil {
-		level.Error(sc.logger).Log("msg", "Unable to collect cluster nodes status")
-		return
+		return nil, nil, fmt.Errorf("unable to collect cluster nodes status: %s", err)
 	}
 
-	controllerNodeStatusMetrics = sc.extractControllerStatusMetrics(clusterNodes.ControllerCluster)
-	managementNodeMetrics = sc.extractManagementNodeMetrics(clusterNodes.ManagementCluster)
+	controllerNodeStatusMetrics := sc.extractControllerStatusMetrics(clusterNodes.ControllerCluster)
+	managementNodeMetrics := sc.extractManagementNodeMetrics(clusterNodes.ManagementCluster)
 
-	return
+	return controllerNodeStatusMetrics, managementNodeMetrics, nil
 }
 
 func (sc *systemCollector) extractControllerStatusMetrics(controllerNodes []administration.ControllerNodeAggregateInfo) (controllerNodeStatusMetrics []controllerNodeStatusMetric) {
@@ -308,8 +328,8 @@ func (sc *systemCollector) extractManagementNodeMetrics(managementNodes []admini
 	return
 }
 
-func (sc *systemCollector) collectServiceStatusMetrics() (serviceStatusMetrics []serviceStatusMetric) {
-	var collectors []func() (serviceStatusMetric, error)
+func (sc *systemCollector) collectServiceStatusMetrics(ctx context.Context) (serviceStatusMetrics []serviceStatusMetric) {
+	var collectors []func(ctx context.Context) (serviceStatusMetric, error)
 	collectors = append(collectors, sc.collectApplianceServiceMetric)
 	collectors = append(collectors, sc.collectMessageBusServiceMetric)
 	collectors = append(collectors, sc.collectNTPServiceMetric)
@@ -323,7 +343,10 @@ func (sc *systemCollector) collectServiceStatusMetrics() (serviceStatusMetrics [
 	collectors = append(collectors, sc.collectSyslogServiceMetric)
 
 	for _, collectServiceStatusMetric := range collectors {
-		m, err := collectServiceStatusMetric()
+		if ctx.Err() != nil {
+			break
+		}
+		m, err := collectServiceStatusMetric(ctx)
 		if err != nil {
 			level.Error(sc.logger).Log("msg", "Unable to collect system service status", "name", m.Name, "error", err.Error())
 			continue
@@ -333,8 +356,8 @@ func (sc *systemCollector) collectServiceStatusMetrics() (serviceStatusMetrics [
 	return
 }
 
-func (sc *systemCollector) collectServiceStatusMetric(name string, collectSystemService func() (administration.NodeServiceStatusProperties, error)) (serviceStatusMetric, error) {
-	status, err := collectSystemService()
+func (sc *systemCollector) collectServiceStatusMetric(ctx context.Context, name string, collectSystemService func(ctx context.Context) (administration.NodeServiceStatusProperties, error)) (serviceStatusMetric, error) {
+	status, err := collectSystemService(ctx)
 	if err != nil {
 		return serviceStatusMetric{}, err
 	}
@@ -352,50 +375,50 @@ func (sc *systemCollector) collectServiceStatusMetric(name string, collectSystem
 	return statusMetric, nil
 }
 
-func (sc *systemCollector) collectApplianceServiceMetric() (serviceStatusMetric, error) {
-	return sc.collectServiceStatusMetric("appliance", sc.systemClient.ReadApplianceManagementServiceStatus)
+func (sc *systemCollector) collectApplianceServiceMetric(ctx context.Context) (serviceStatusMetric, error) {
+	return sc.collectServiceStatusMetric(ctx, "appliance", sc.systemClient.ReadApplianceManagementServiceStatus)
 }
 
-func (sc *systemCollector) collectMessageBusServiceMetric() (serviceStatusMetric, error) {
-	return sc.collectServiceStatusMetric("message_bus", sc.systemClient.ReadNSXMessageBusServiceStatus)
+func (sc *systemCollector) collectMessageBusServiceMetric(ctx context.Context) (serviceStatusMetric, error) {
+	return sc.collectServiceStatusMetric(ctx, "message_bus", sc.systemClient.ReadNSXMessageBusServiceStatus)
 }
 
-func (sc *systemCollector) collectNTPServiceMetric() (serviceStatusMetric, error) {
-	return sc.collectServiceStatusMetric("ntp", sc.systemClient.ReadNTPServiceStatus)
+func (sc *systemCollector) collectNTPServiceMetric(ctx context.Context) (serviceStatusMetric, error) {
+	return sc.collectServiceStatusMetric(ctx, "ntp", sc.systemClient.ReadNTPServiceStatus)
 }
 
-func (sc *systemCollector) collectUpgradeAgentServiceMetric() (serviceStatusMetric, error) {
-	return sc.collectServiceStatusMetric("upgrade_agent", sc.systemClient.ReadNsxUpgradeAgentServiceStatus)
+func (sc *systemCollector) collectUpgradeAgentServiceMetric(ctx context.Context) (serviceStatusMetric, error) {
+	return sc.collectServiceStatusMetric(ctx, "upgrade_agent", sc.systemClient.ReadNsxUpgradeAgentServiceStatus)
 }
 
-func (sc *systemCollector) collectProtonServiceMetric() (serviceStatusMetric, error) {
-	return sc.collectServiceStatusMetric("proton", sc.systemClient.ReadProtonServiceStatus)
+func (sc *systemCollector) collectProtonServiceMetric(ctx context.Context) (serviceStatusMetric, error) {
+	return sc.collectServiceStatusMetric(ctx, "proton", sc.systemClient.ReadProtonServiceStatus)
 }
 
-func (sc *systemCollector) collectProxyServiceMetric() (serviceStatusMetric, error) {
-	return sc.collectServiceStatusMetric("proxy", sc.systemClient.ReadProxyServiceStatus)
+func (sc *systemCollector) collectProxyServiceMetric(ctx context.Context) (serviceStatusMetric, error) {
+	return sc.collectServiceStatusMetric(ctx, "proxy", sc.systemClient.ReadProxyServiceStatus)
 }
 
-func (sc *systemCollector) collectRabbitMQServiceMetric() (serviceStatusMetric, error) {
-	return sc.collectServiceStatusMetric("rabbitmq", sc.systemClient.ReadRabbitMQServiceStatus)
+func (sc *systemCollector) collectRabbitMQServiceMetric(ctx context.Context) (serviceStatusMetric, error) {
+	return sc.collectServiceStatusMetric(ctx, "rabbitmq", sc.systemClient.ReadRabbitMQServiceStatus)
 }
 
-func (sc *systemCollector) collectRepositoryServiceMetric() (serviceStatusMetric, error) {
-	return sc.collectServiceStatusMetric("repository", sc.systemClient.ReadRepositoryServiceStatus)
+func (sc *systemCollector) collectRepositoryServiceMetric(ctx context.Context) (serviceStatusMetric, error) {
+	return sc.collectServiceStatusMetric(ctx, "repository", sc.systemClient.ReadRepositoryServiceStatus)
 }
 
-func (sc *systemCollector) collectSNMPServiceMetric() (serviceStatusMetric, error) {
-	return sc.collectServiceStatusMetric("snmp", sc.systemClient.ReadSNMPServiceStatus)
+func (sc *systemCollector) collectSNMPServiceMetric(ctx context.Context) (serviceStatusMetric, error) {
+	return sc.collectServiceStatusMetric(ctx, "snmp", sc.systemClient.ReadSNMPServiceStatus)
 }
 
-func (sc *systemCollector) collectSSHServiceMetric() (serviceStatusMetric, error) {
-	return sc.collectServiceStatusMetric("ssh", sc.systemClient.ReadSSHServiceStatus)
+func (sc *systemCollector) collectSSHServiceMetric(ctx context.Context) (serviceStatusMetric, error) {
+	return sc.collectServiceStatusMetric(ctx, "ssh", sc.systemClient.ReadSSHServiceStatus)
 }
 
-func (sc *systemCollector) collectSearchServiceMetric() (serviceStatusMetric, error) {
-	return sc.collectServiceStatusMetric("search", sc.systemClient.ReadSearchServiceStatus)
+func (sc *systemCollector) collectSearchServiceMetric(ctx context.Context) (serviceStatusMetric, error) {
+	return sc.collectServiceStatusMetric(ctx, "search", sc.systemClient.ReadSearchServiceStatus)
 }
 
-func (sc *systemCollector) collectSyslogServiceMetric() (serviceStatusMetric, error) {
-	return sc.collectServiceStatusMetric("syslog", sc.systemClient.ReadSyslogServiceStatus)
+func (sc *systemCollector) collectSyslogServiceMetric(ctx context.Context) (serviceStatusMetric, error) {
+	return sc.collectServiceStatusMetric(ctx, "syslog", sc.systemClient.ReadSyslogServiceStatus)
 }
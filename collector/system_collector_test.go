@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"testing"
@@ -53,7 +54,7 @@ type mockControlClusterStatus struct {
 	MgmtConnectivityStatus string
 }
 
-func (c *mockSystemClient) ReadClusterStatus() (administration.ClusterStatus, error) {
+func (c *mockSystemClient) ReadClusterStatus(ctx context.Context) (administration.ClusterStatus, error) {
 	if c.clusterStatusResponse.Error != nil {
 		return administration.ClusterStatus{}, c.clusterStatusResponse.Error
 	}
@@ -67,7 +68,7 @@ func (c *mockSystemClient) ReadClusterStatus() (administration.ClusterStatus, er
 	}, nil
 }
 
-func (c *mockSystemClient) ReadClusterNodesAggregateStatus() (administration.ClustersAggregateInfo, error) {
+func (c *mockSystemClient) ReadClusterNodesAggregateStatus(ctx context.Context) (administration.ClustersAggregateInfo, error) {
 	if c.clusterNodeStatusResponse.Error != nil {
 		return administration.ClustersAggregateInfo{}, c.clusterNodeStatusResponse.Error
 	}
@@ -141,51 +142,51 @@ func (c *mockSystemClient) buildServiceStatusResponse() (administration.NodeServ
 	}, nil
 }
 
-func (c *mockSystemClient) ReadApplianceManagementServiceStatus() (administration.NodeServiceStatusProperties, error) {
+func (c *mockSystemClient) ReadApplianceManagementServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
 	return c.buildServiceStatusResponse()
 }
 
-func (c *mockSystemClient) ReadNSXMessageBusServiceStatus() (administration.NodeServiceStatusProperties, error) {
+func (c *mockSystemClient) ReadNSXMessageBusServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
 	return c.buildServiceStatusResponse()
 }
 
-func (c *mockSystemClient) ReadNTPServiceStatus() (administration.NodeServiceStatusProperties, error) {
+func (c *mockSystemClient) ReadNTPServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
 	return c.buildServiceStatusResponse()
 }
 
-func (c *mockSystemClient) ReadNsxUpgradeAgentServiceStatus() (administration.NodeServiceStatusProperties, error) {
+func (c *mockSystemClient) ReadNsxUpgradeAgentServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
 	return c.buildServiceStatusResponse()
 }
 
-func (c *mockSystemClient) ReadProtonServiceStatus() (administration.NodeServiceStatusProperties, error) {
+func (c *mockSystemClient) ReadProtonServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
 	return c.buildServiceStatusResponse()
 }
 
-func (c *mockSystemClient) ReadProxyServiceStatus() (administration.NodeServiceStatusProperties, error) {
+func (c *mockSystemClient) ReadProxyServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
 	return c.buildServiceStatusResponse()
 }
 
-func (c *mockSystemClient) ReadRabbitMQServiceStatus() (administration.NodeServiceStatusProperties, error) {
+func (c *mockSystemClient) ReadRabbitMQServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
 	return c.buildServiceStatusResponse()
 }
 
-func (c *mockSystemClient) ReadRepositoryServiceStatus() (administration.NodeServiceStatusProperties, error) {
+func (c *mockSystemClient) ReadRepositoryServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
 	return c.buildServiceStatusResponse()
 }
 
-func (c *mockSystemClient) ReadSNMPServiceStatus() (administration.NodeServiceStatusProperties, error) {
+func (c *mockSystemClient) ReadSNMPServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
 	return c.buildServiceStatusResponse()
 }
 
-func (c *mockSystemClient) ReadSSHServiceStatus() (administration.NodeServiceStatusProperties, error) {
+func (c *mockSystemClient) ReadSSHServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
 	return c.buildServiceStatusResponse()
 }
 
-func (c *mockSystemClient) ReadSearchServiceStatus() (administration.NodeServiceStatusProperties, error) {
+func (c *mockSystemClient) ReadSearchServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
 	return c.buildServiceStatusResponse()
 }
 
-func (c *mockSystemClient) ReadSyslogServiceStatus() (administration.NodeServiceStatusProperties, error) {
+func (c *mockSystemClient) ReadSyslogServiceStatus(ctx context.Context) (administration.NodeServiceStatusProperties, error) {
 	return c.buildServiceStatusResponse()
 }
 
@@ -334,7 +335,12 @@ func TestSystemCollector_CollectClusterStatusMetrics(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		systemCollector := newSystemCollector(mockSystemClient, logger)
-		clusterMetrics := systemCollector.collectClusterStatusMetrics()
+		clusterMetrics, err := systemCollector.collectClusterStatusMetrics(context.Background())
+		if tc.response.Error != nil {
+			assert.Error(t, err, tc.description)
+		} else {
+			assert.NoError(t, err, tc.description)
+		}
 		assert.ElementsMatch(t, tc.expectedMetrics, clusterMetrics, tc.description)
 	}
 }
@@ -513,7 +519,12 @@ func TestSystemCollector_CollectClusterNodeMetrics(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		systemCollector := newSystemCollector(mockSystemClient, logger)
-		controllerNodeMetrics, nodeMetrics := systemCollector.collectClusterNodeMetrics()
+		controllerNodeMetrics, nodeMetrics, err := systemCollector.collectClusterNodeMetrics(context.Background())
+		if tc.response.Error != nil {
+			assert.Error(t, err, tc.description)
+		} else {
+			assert.NoError(t, err, tc.description)
+		}
 		assert.ElementsMatch(t, tc.expectedControllerNodeStatusMetrics, controllerNodeMetrics, tc.description)
 		assert.ElementsMatch(t, tc.expectedManagementNodeMetrics, nodeMetrics, tc.description)
 	}
@@ -552,7 +563,7 @@ func TestSystemCollector_CollectApplianceServiceMetric(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		systemCollector := newSystemCollector(mockSystemClient, logger)
-		serviceMetric, err := systemCollector.collectApplianceServiceMetric()
+		serviceMetric, err := systemCollector.collectApplianceServiceMetric(context.Background())
 		assert.Equal(t, tc.expectedMetric, serviceMetric, tc.description)
 		if reflect.DeepEqual(tc.expectedMetric, serviceStatusMetric{}) {
 			assert.Error(t, err)
@@ -593,7 +604,7 @@ func TestSystemCollector_CollectMessageBusServiceMetric(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		systemCollector := newSystemCollector(mockSystemClient, logger)
-		serviceMetric, err := systemCollector.collectMessageBusServiceMetric()
+		serviceMetric, err := systemCollector.collectMessageBusServiceMetric(context.Background())
 		assert.Equal(t, tc.expectedMetric, serviceMetric, tc.description)
 		if reflect.DeepEqual(tc.expectedMetric, serviceStatusMetric{}) {
 			assert.Error(t, err)
@@ -634,7 +645,7 @@ func TestSystemCollector_CollectNTPServiceMetric(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		systemCollector := newSystemCollector(mockSystemClient, logger)
-		serviceMetric, err := systemCollector.collectNTPServiceMetric()
+		serviceMetric, err := systemCollector.collectNTPServiceMetric(context.Background())
 		assert.Equal(t, tc.expectedMetric, serviceMetric, tc.description)
 		if reflect.DeepEqual(tc.expectedMetric, serviceStatusMetric{}) {
 			assert.Error(t, err)
@@ -675,7 +686,7 @@ func TestSystemCollector_CollectUpgradeServiceMetric(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		systemCollector := newSystemCollector(mockSystemClient, logger)
-		serviceMetric, err := systemCollector.collectUpgradeAgentServiceMetric()
+		serviceMetric, err := systemCollector.collectUpgradeAgentServiceMetric(context.Background())
 		assert.Equal(t, tc.expectedMetric, serviceMetric, tc.description)
 		if reflect.DeepEqual(tc.expectedMetric, serviceStatusMetric{}) {
 			assert.Error(t, err)
@@ -716,7 +727,7 @@ func TestSystemCollector_CollectProtonServiceMetric(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		systemCollector := newSystemCollector(mockSystemClient, logger)
-		serviceMetric, err := systemCollector.collectProtonServiceMetric()
+		serviceMetric, err := systemCollector.collectProtonServiceMetric(context.Background())
 		assert.Equal(t, tc.expectedMetric, serviceMetric, tc.description)
 		if reflect.DeepEqual(tc.expectedMetric, serviceStatusMetric{}) {
 			assert.Error(t, err)
@@ -757,7 +768,7 @@ func TestSystemCollector_CollectProxyServiceMetric(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		systemCollector := newSystemCollector(mockSystemClient, logger)
-		serviceMetric, err := systemCollector.collectProxyServiceMetric()
+		serviceMetric, err := systemCollector.collectProxyServiceMetric(context.Background())
 		assert.Equal(t, tc.expectedMetric, serviceMetric, tc.description)
 		if reflect.DeepEqual(tc.expectedMetric, serviceStatusMetric{}) {
 			assert.Error(t, err)
@@ -798,7 +809,7 @@ func TestSystemCollector_CollectRabbitMQServiceMetric(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		systemCollector := newSystemCollector(mockSystemClient, logger)
-		serviceMetric, err := systemCollector.collectRabbitMQServiceMetric()
+		serviceMetric, err := systemCollector.collectRabbitMQServiceMetric(context.Background())
 		assert.Equal(t, tc.expectedMetric, serviceMetric, tc.description)
 		if reflect.DeepEqual(tc.expectedMetric, serviceStatusMetric{}) {
 			assert.Error(t, err)
@@ -839,7 +850,7 @@ func TestSystemCollector_CollectRepositoryServiceMetric(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		systemCollector := newSystemCollector(mockSystemClient, logger)
-		serviceMetric, err := systemCollector.collectRepositoryServiceMetric()
+		serviceMetric, err := systemCollector.collectRepositoryServiceMetric(context.Background())
 		assert.Equal(t, tc.expectedMetric, serviceMetric, tc.description)
 		if reflect.DeepEqual(tc.expectedMetric, serviceStatusMetric{}) {
 			assert.Error(t, err)
@@ -880,7 +891,7 @@ func TestSystemCollector_CollectSNMPServiceMetric(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		systemCollector := newSystemCollector(mockSystemClient, logger)
-		serviceMetric, err := systemCollector.collectSNMPServiceMetric()
+		serviceMetric, err := systemCollector.collectSNMPServiceMetric(context.Background())
 		assert.Equal(t, tc.expectedMetric, serviceMetric, tc.description)
 		if reflect.DeepEqual(tc.expectedMetric, serviceStatusMetric{}) {
 			assert.Error(t, err)
@@ -921,7 +932,7 @@ func TestSystemCollector_CollectSSHServiceMetric(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		systemCollector := newSystemCollector(mockSystemClient, logger)
-		serviceMetric, err := systemCollector.collectSSHServiceMetric()
+		serviceMetric, err := systemCollector.collectSSHServiceMetric(context.Background())
 		assert.Equal(t, tc.expectedMetric, serviceMetric, tc.description)
 		if reflect.DeepEqual(tc.expectedMetric, serviceStatusMetric{}) {
 			assert.Error(t, err)
@@ -962,7 +973,7 @@ func TestSystemCollector_CollectSearchServiceMetric(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		systemCollector := newSystemCollector(mockSystemClient, logger)
-		serviceMetric, err := systemCollector.collectSearchServiceMetric()
+		serviceMetric, err := systemCollector.collectSearchServiceMetric(context.Background())
 		assert.Equal(t, tc.expectedMetric, serviceMetric, tc.description)
 		if reflect.DeepEqual(tc.expectedMetric, serviceStatusMetric{}) {
 			assert.Error(t, err)
@@ -1003,7 +1014,7 @@ func TestSystemCollector_CollectSyslogServiceMetric(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		systemCollector := newSystemCollector(mockSystemClient, logger)
-		serviceMetric, err := systemCollector.collectSyslogServiceMetric()
+		serviceMetric, err := systemCollector.collectSyslogServiceMetric(context.Background())
 		assert.Equal(t, tc.expectedMetric, serviceMetric, tc.description)
 		if reflect.DeepEqual(tc.expectedMetric, serviceStatusMetric{}) {
 			assert.Error(t, err)
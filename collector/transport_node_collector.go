@@ -1,22 +1,24 @@
 package collector
 
 import (
+	"context"
+	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 
 	"nsxt_exporter/client"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
-	nsxt "github.com/vmware/go-vmware-nsxt"
 	"github.com/vmware/go-vmware-nsxt/manager"
 )
 
 var transportNodePossibleStatus = [...]string{"UP", "DOWN", "DEGRADED", "UNKNOWN"}
 
 func init() {
-	registerCollector("transport_node", createTransportNodeCollectorFactory)
+	registerCollector("transport_node", defaultEnabled, createTransportNodeCollectorFactory)
 }
 
 type edgeClusterMembership struct {
@@ -41,7 +43,7 @@ type transportNodeMetric struct {
 	TransportZoneIDs []string
 }
 
-func createTransportNodeCollectorFactory(apiClient *nsxt.APIClient, logger log.Logger) prometheus.Collector {
+func createTransportNodeCollectorFactory(apiClient *client.APIClient, logger log.Logger) Collector {
 	nsxtClient := client.NewNSXTClient(apiClient, logger)
 	return newTransportNodeCollector(nsxtClient, logger)
 }
@@ -67,20 +69,19 @@ func newTransportNodeCollector(transportNodeClient client.TransportNodeClient, l
 	}
 }
 
-// Describe implements the prometheus.Collector interface.
+// Describe implements the Collector interface.
 func (c *transportNodeCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.transportNodeStatus
 	ch <- c.edgeClusterMembership
 }
 
-// Collect implements the prometheus.Collector interface.
-func (c *transportNodeCollector) Collect(ch chan<- prometheus.Metric) {
-	transportNodes, err := c.transportNodeClient.ListAllTransportNodes()
+// Update implements the Collector interface.
+func (c *transportNodeCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	transportNodes, err := c.transportNodeClient.ListAllTransportNodes(ctx)
 	if err != nil {
-		level.Error(c.logger).Log("msg", "Unable to list transport nodes", "err", err)
-		return
+		return fmt.Errorf("unable to list transport nodes: %s", err)
 	}
-	edgeClusterMemberships, err := c.generateEdgeClusterMemberships()
+	edgeClusterMemberships, err := c.generateEdgeClusterMemberships(ctx)
 	if err != nil {
 		edgeClusterMemberships = nil
 		level.Error(c.logger).Log("msg", "Unable to generate edge cluster membership", "err", err)
@@ -88,7 +89,7 @@ func (c *transportNodeCollector) Collect(ch chan<- prometheus.Metric) {
 	for _, membership := range edgeClusterMemberships {
 		ch <- c.buildEdgeClusterMembershipMetrics(membership)
 	}
-	transportNodeMetrics := c.generateTransportNodeMetrics(transportNodes, edgeClusterMemberships)
+	transportNodeMetrics := c.generateTransportNodeMetrics(ctx, transportNodes, edgeClusterMemberships)
 	for _, tnMetric := range transportNodeMetrics {
 		for _, tzID := range tnMetric.TransportZoneIDs {
 			for status, value := range tnMetric.StatusDetail {
@@ -96,6 +97,7 @@ func (c *transportNodeCollector) Collect(ch chan<- prometheus.Metric) {
 			}
 		}
 	}
+	return nil
 }
 
 func (c *transportNodeCollector) buildEdgeClusterMembershipMetrics(membership edgeClusterMembership) prometheus.Metric {
@@ -109,12 +111,14 @@ func (c *transportNodeCollector) buildEdgeClusterMembershipMetrics(membership ed
 	)
 }
 
-func (c *transportNodeCollector) generateTransportNodeMetrics(transportNodes []manager.TransportNode, edgeClusterMemberships []edgeClusterMembership) (transportNodeMetrics []transportNodeMetric) {
-	for _, transportNode := range transportNodes {
-		transportNodeStatus, err := c.transportNodeClient.GetTransportNodeStatus(transportNode.Id)
+func (c *transportNodeCollector) generateTransportNodeMetrics(ctx context.Context, transportNodes []manager.TransportNode, edgeClusterMemberships []edgeClusterMembership) (transportNodeMetrics []transportNodeMetric) {
+	var mtx sync.Mutex
+	forEach(ctx, "transport_node", len(transportNodes), func(i int) {
+		transportNode := transportNodes[i]
+		transportNodeStatus, err := c.transportNodeClient.GetTransportNodeStatus(ctx, transportNode.Id)
 		if err != nil {
 			level.Error(c.logger).Log("msg", "Unable to get transport node status", "id", transportNode.Id, "err", err)
-			continue
+			return
 		}
 		statusDetail := map[string]float64{}
 		for _, status := range transportNodePossibleStatus {
@@ -146,14 +150,16 @@ func (c *transportNodeCollector) generateTransportNodeMetrics(transportNodes []m
 			TransportZoneIDs: transportZoneIDs,
 			StatusDetail:     statusDetail,
 		}
+		mtx.Lock()
 		transportNodeMetrics = append(transportNodeMetrics, transportNodeMetric)
-	}
+		mtx.Unlock()
+	})
 	return
 }
 
-func (c *transportNodeCollector) generateEdgeClusterMemberships() ([]edgeClusterMembership, error) {
+func (c *transportNodeCollector) generateEdgeClusterMemberships(ctx context.Context) ([]edgeClusterMembership, error) {
 	var edgeClusterMemberships []edgeClusterMembership
-	edgeClusters, err := c.transportNodeClient.ListAllEdgeClusters()
+	edgeClusters, err := c.transportNodeClient.ListAllEdgeClusters(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -38,11 +39,11 @@ type transportNodeClientMock struct {
 	transportNodeStatusResponses []transportNodeStatusResponse
 }
 
-func (c *transportNodeClientMock) ListAllTransportNodes() ([]manager.TransportNode, error) {
+func (c *transportNodeClientMock) ListAllTransportNodes(ctx context.Context) ([]manager.TransportNode, error) {
 	panic("implement me")
 }
 
-func (c *transportNodeClientMock) GetTransportNodeStatus(nodeID string) (manager.TransportNodeStatus, error) {
+func (c *transportNodeClientMock) GetTransportNodeStatus(ctx context.Context, nodeID string) (manager.TransportNodeStatus, error) {
 	for _, response := range c.transportNodeStatusResponses {
 		if response.ID == nodeID {
 			return manager.TransportNodeStatus{
@@ -53,7 +54,7 @@ func (c *transportNodeClientMock) GetTransportNodeStatus(nodeID string) (manager
 	return manager.TransportNodeStatus{}, errors.New("transport node status not foud")
 }
 
-func (c *transportNodeClientMock) ListAllEdgeClusters() ([]manager.EdgeCluster, error) {
+func (c *transportNodeClientMock) ListAllEdgeClusters(ctx context.Context) ([]manager.EdgeCluster, error) {
 	return c.edgeClustersResponse, c.edgeClustersError
 }
 
@@ -141,7 +142,7 @@ func TestTransportNodeCollector_GenerateEdgeClusterMemberships(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		collector := newTransportNodeCollector(client, logger)
-		memberships, err := collector.generateEdgeClusterMemberships()
+		memberships, err := collector.generateEdgeClusterMemberships(context.Background())
 		if tc.expectingError {
 			assert.Error(t, err, tc.description)
 		}
@@ -361,7 +362,7 @@ func TestTransportNodeCollector_GenerateTransportNodeMetrics(t *testing.T) {
 		}
 		logger := log.NewNopLogger()
 		collector := newTransportNodeCollector(client, logger)
-		metrics := collector.generateTransportNodeMetrics(tc.transportNodes, tc.edgeClusterMemberships)
+		metrics := collector.generateTransportNodeMetrics(context.Background(), tc.transportNodes, tc.edgeClusterMemberships)
 		assert.ElementsMatch(t, tc.expectedMetrics, metrics, tc.description)
 	}
 }
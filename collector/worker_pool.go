@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"context"
+	"sync"
+
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	maxConcurrency     = kingpin.Flag("collector.max-concurrency", "Maximum number of concurrent per-object requests of all collectors to the NSX-T manager. No limit if 0.").Default("10").Int()
+	concurrencyLimits  = make(map[string]*int)
+	semaphoresOnce     sync.Once
+	globalSemaphore    semaphore
+	collectorSemaphore map[string]semaphore
+)
+
+// semaphore limits the number of concurrent calls. A nil semaphore imposes no
+// limit.
+type semaphore chan struct{}
+
+func newSemaphore(limit int) semaphore {
+	if limit <= 0 {
+		return nil
+	}
+	return make(semaphore, limit)
+}
+
+func (s semaphore) acquire(ctx context.Context) bool {
+	if s == nil {
+		return ctx.Err() == nil
+	}
+	select {
+	case s <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s semaphore) release() {
+	if s != nil {
+		<-s
+	}
+}
+
+// semaphores returns the global semaphore and the semaphore of the given
+// collector. They are created on first use, once flags are parsed.
+func semaphores(collector string) (semaphore, semaphore) {
+	semaphoresOnce.Do(func() {
+		globalSemaphore = newSemaphore(*maxConcurrency)
+		collectorSemaphore = make(map[string]semaphore)
+		for name, limit := range concurrencyLimits {
+			collectorSemaphore[name] = newSemaphore(*limit)
+		}
+	})
+	return globalSemaphore, collectorSemaphore[collector]
+}
+
+// forEach calls fn for the indexes 0 to n-1 concurrently, running at most as
+// many calls at once as the limit of the collector and the global limit
+// allow. It stops starting calls once ctx is done and returns when all
+// started calls returned.
+func forEach(ctx context.Context, collector string, n int, fn func(i int)) {
+	global, local := semaphores(collector)
+	wg := sync.WaitGroup{}
+	for i := 0; i < n; i++ {
+		if !local.acquire(ctx) {
+			break
+		}
+		if !global.acquire(ctx) {
+			local.release()
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer local.release()
+			defer global.release()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
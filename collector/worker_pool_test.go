@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEach(t *testing.T) {
+	semaphores("")
+	collectorSemaphore["fake_limited"] = newSemaphore(2)
+	t.Cleanup(func() {
+		delete(collectorSemaphore, "fake_limited")
+	})
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	testcases := []struct {
+		description           string
+		ctx                   context.Context
+		collector             string
+		n                     int
+		expectedCalls         int
+		expectedMaxConcurrent int
+	}{
+		{
+			description:           "Should call every index at most as concurrent as the collector limit",
+			ctx:                   context.Background(),
+			collector:             "fake_limited",
+			n:                     20,
+			expectedCalls:         20,
+			expectedMaxConcurrent: 2,
+		},
+		{
+			description:           "Should call every index at once when there's no limit",
+			ctx:                   context.Background(),
+			collector:             "fake_unlimited",
+			n:                     20,
+			expectedCalls:         20,
+			expectedMaxConcurrent: 20,
+		},
+		{
+			description:   "Should not call any index when context is done",
+			ctx:           cancelledCtx,
+			collector:     "fake_limited",
+			n:             20,
+			expectedCalls: 0,
+		},
+	}
+	for _, tc := range testcases {
+		var mtx sync.Mutex
+		calls, running, maxRunning := 0, 0, 0
+		// Calls block until expectedMaxConcurrent of them run at once, and
+		// some more time to let calls beyond the limit start if it is broken.
+		barrier := make(chan struct{})
+		var releaseOnce sync.Once
+		forEach(tc.ctx, tc.collector, tc.n, func(i int) {
+			mtx.Lock()
+			calls++
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			if running == tc.expectedMaxConcurrent {
+				releaseOnce.Do(func() {
+					time.AfterFunc(50*time.Millisecond, func() { close(barrier) })
+				})
+			}
+			mtx.Unlock()
+			select {
+			case <-barrier:
+			case <-time.After(5 * time.Second):
+				t.Errorf("%s: timed out waiting for %d concurrent calls", tc.description, tc.expectedMaxConcurrent)
+			}
+			mtx.Lock()
+			running--
+			mtx.Unlock()
+		})
+		assert.Equal(t, tc.expectedCalls, calls, tc.description)
+		assert.Equal(t, tc.expectedMaxConcurrent, maxRunning, tc.description)
+	}
+}
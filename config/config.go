@@ -0,0 +1,154 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Config is the configuration read from the exporter configuration file.
+type Config struct {
+	Modules map[string]Module `yaml:"modules"`
+}
+
+// DefaultTimeout is the timeout of a single NSX-T API request of modules that
+// don't set one. It is below the default scrape timeout of Prometheus of 10s
+// less the default scrape timeout offset of 500ms.
+const DefaultTimeout = 9 * time.Second
+
+// Module holds the settings used to connect to and scrape a NSX-T manager.
+type Module struct {
+	Username     string            `yaml:"username"`
+	UsernameFile string            `yaml:"username_file"`
+	Password     string            `yaml:"password"`
+	PasswordFile string            `yaml:"password_file"`
+	CAFile       string            `yaml:"ca_file"`
+	CertFile     string            `yaml:"cert_file"`
+	KeyFile      string            `yaml:"key_file"`
+	Thumbprint   string            `yaml:"thumbprint"`
+	ServerName   string            `yaml:"server_name"`
+	Insecure     bool              `yaml:"insecure"`
+	Timeout      time.Duration     `yaml:"timeout"`
+	Collectors   []string          `yaml:"collectors"`
+	Targets      []string          `yaml:"targets"`
+	Labels       map[string]string `yaml:"labels"`
+}
+
+// LoadFile parses and validates the configuration file with the given name.
+func LoadFile(filename string) (*Config, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %s", filename, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %s", filename, err)
+	}
+	for name, module := range cfg.Modules {
+		if module.Timeout == 0 {
+			module.Timeout = DefaultTimeout
+			cfg.Modules[name] = module
+		}
+	}
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	for name, module := range c.Modules {
+		if name == "" {
+			return fmt.Errorf("module name must not be empty")
+		}
+		if err := module.Validate(); err != nil {
+			return fmt.Errorf("module %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// Validate returns an error if the settings of the module are inconsistent or
+// refer to files that cannot be read.
+func (m *Module) Validate() error {
+	if m.Username == "" && m.UsernameFile == "" && m.CertFile == "" {
+		return fmt.Errorf("username, username_file or cert_file must be set")
+	}
+	if m.Username != "" && m.UsernameFile != "" {
+		return fmt.Errorf("at most one of username and username_file must be set")
+	}
+	if m.Password != "" && m.PasswordFile != "" {
+		return fmt.Errorf("at most one of password and password_file must be set")
+	}
+	if (m.CertFile == "") != (m.KeyFile == "") {
+		return fmt.Errorf("cert_file and key_file must be set together")
+	}
+	if m.Insecure && (m.CAFile != "" || m.ServerName != "") {
+		return fmt.Errorf("insecure must not be set together with ca_file or server_name")
+	}
+	if m.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
+	if m.CAFile != "" {
+		ca, err := ioutil.ReadFile(m.CAFile)
+		if err != nil {
+			return fmt.Errorf("unable to read CA file: %s", err)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(ca) {
+			return fmt.Errorf("no certificates found in CA file %s", m.CAFile)
+		}
+	}
+	for _, file := range []string{m.UsernameFile, m.PasswordFile} {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("unable to read credentials: %s", err)
+		}
+	}
+	for _, file := range []string{m.CertFile, m.KeyFile} {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("unable to read client certificate: %s", err)
+		}
+	}
+	if m.Thumbprint != "" {
+		if _, err := ParseThumbprint(m.Thumbprint); err != nil {
+			return err
+		}
+	}
+	for _, target := range m.Targets {
+		if target == "" || strings.Contains(target, ",") {
+			return fmt.Errorf("invalid target %q, targets must be a single host", target)
+		}
+	}
+	for name := range m.Labels {
+		if !model.LabelName(name).IsValid() {
+			return fmt.Errorf("invalid label name %q", name)
+		}
+	}
+	return nil
+}
+
+// ParseThumbprint parses a SHA-256 certificate thumbprint given in hex, with
+// or without colons.
+func ParseThumbprint(thumbprint string) ([]byte, error) {
+	sum, err := hex.DecodeString(strings.ReplaceAll(thumbprint, ":", ""))
+	if err != nil || len(sum) != sha256.Size {
+		return nil, fmt.Errorf("invalid SHA-256 thumbprint %q", thumbprint)
+	}
+	return sum, nil
+}
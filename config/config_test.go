@@ -0,0 +1,121 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFile(t *testing.T) {
+	cfg, err := LoadFile("testdata/valid.yml")
+	assert.NoError(t, err)
+	expected := &Config{
+		Modules: map[string]Module{
+			"default": {
+				Username: "admin",
+				Password: "secret",
+				Insecure: true,
+				Timeout:  DefaultTimeout,
+			},
+			"site_a": {
+				Username:   "monitoring",
+				Password:   "secret",
+				CAFile:     "testdata/ca.pem",
+				Thumbprint: "EB:F5:EF:1C:72:8E:76:4A:DA:54:9E:DC:22:A4:AC:4F:DE:38:73:35:9B:D0:EC:52:40:E3:B3:94:7D:58:F5:AD",
+				ServerName: "nsx.example.com",
+				Timeout:    30 * time.Second,
+				Collectors: []string{"system", "transport_node"},
+				Targets:    []string{"nsx-a.example.com"},
+				Labels:     map[string]string{"site": "site-a"},
+			},
+			"principal": {
+				CertFile: "testdata/client.pem",
+				KeyFile:  "testdata/client-key.pem",
+				CAFile:   "testdata/ca.pem",
+				Timeout:  DefaultTimeout,
+			},
+			"from_files": {
+				UsernameFile: "testdata/username",
+				PasswordFile: "testdata/password",
+				Timeout:      DefaultTimeout,
+			},
+		},
+	}
+	assert.Equal(t, expected, cfg)
+}
+
+func TestLoadFile_Invalid(t *testing.T) {
+	testcases := []struct {
+		description   string
+		filename      string
+		expectedError string
+	}{
+		{
+			description:   "Should return error when file does not exist",
+			filename:      "testdata/does-not-exist.yml",
+			expectedError: "no such file or directory",
+		},
+		{
+			description:   "Should return error when field is unknown",
+			filename:      "testdata/unknown_field.yml",
+			expectedError: "field hostname not found",
+		},
+		{
+			description:   "Should return error when username and client certificate are missing",
+			filename:      "testdata/missing_username.yml",
+			expectedError: "module default: username, username_file or cert_file must be set",
+		},
+		{
+			description:   "Should return error when both password and password file are set",
+			filename:      "testdata/duplicate_password.yml",
+			expectedError: "module default: at most one of password and password_file must be set",
+		},
+		{
+			description:   "Should return error when client certificate has no key",
+			filename:      "testdata/missing_key_file.yml",
+			expectedError: "module default: cert_file and key_file must be set together",
+		},
+		{
+			description:   "Should return error when insecure is set together with CA file",
+			filename:      "testdata/invalid_insecure.yml",
+			expectedError: "module default: insecure must not be set together with ca_file or server_name",
+		},
+		{
+			description:   "Should return error when timeout is negative",
+			filename:      "testdata/invalid_timeout.yml",
+			expectedError: "module default: timeout must not be negative",
+		},
+		{
+			description:   "Should return error when CA file does not exist",
+			filename:      "testdata/missing_ca_file.yml",
+			expectedError: "module default: unable to read CA file",
+		},
+		{
+			description:   "Should return error when CA file contains no certificates",
+			filename:      "testdata/invalid_ca_file.yml",
+			expectedError: "module default: no certificates found in CA file",
+		},
+		{
+			description:   "Should return error when thumbprint is invalid",
+			filename:      "testdata/invalid_thumbprint.yml",
+			expectedError: `module default: invalid SHA-256 thumbprint "AB:CD"`,
+		},
+		{
+			description:   "Should return error when target lists several hosts",
+			filename:      "testdata/invalid_target.yml",
+			expectedError: `module default: invalid target "nsx-01.example.com,nsx-02.example.com", targets must be a single host`,
+		},
+		{
+			description:   "Should return error when label name is invalid",
+			filename:      "testdata/invalid_label.yml",
+			expectedError: `module default: invalid label name "site-name"`,
+		},
+	}
+	for _, tc := range testcases {
+		_, err := LoadFile(tc.filename)
+		if assert.Error(t, err, tc.description) {
+			assert.Contains(t, err.Error(), tc.expectedError, tc.description)
+		}
+	}
+}
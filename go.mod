@@ -4,9 +4,13 @@ go 1.13
 
 require (
 	github.com/go-kit/kit v0.10.0
-	github.com/prometheus/client_golang v1.6.0
-	github.com/prometheus/common v0.10.0
+	github.com/prometheus/client_golang v1.7.1
+	github.com/prometheus/client_model v0.2.0
+	github.com/prometheus/common v0.15.0
+	github.com/prometheus/exporter-toolkit v0.5.1
 	github.com/stretchr/testify v1.6.0
 	github.com/vmware/go-vmware-nsxt v0.0.0-20200529214410-b51c930ccbfb
+	golang.org/x/time v0.0.0-20191024005414-555d28b269f0
 	gopkg.in/alecthomas/kingpin.v2 v2.2.6
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"nsxt_exporter/client"
+	"nsxt_exporter/collector"
+	"nsxt_exporter/config"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsHandler serves the metrics of the NSX-T manager given by --nsxt.host
+// together with the metrics of the exporter itself.
+type metricsHandler struct {
+	reloader      *reloader
+	timeoutOffset time.Duration
+	logger        log.Logger
+}
+
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	state := h.reloader.acquire()
+	defer state.release()
+	gatherers := prometheus.Gatherers{prometheus.DefaultGatherer}
+	if state.client != nil {
+		module := state.modules[defaultModule]
+		filters, err := collectorFilters(r, module)
+		if err != nil {
+			level.Warn(h.logger).Log("msg", "Couldn't create filtered metrics handler", "err", err)
+			http.Error(w, fmt.Sprintf("Couldn't create filtered metrics handler: %s", err), http.StatusBadRequest)
+			return
+		}
+		ctx, cancel, err := scrapeContext(r, h.timeoutOffset)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Couldn't parse scrape timeout: %s", err), http.StatusBadRequest)
+			return
+		}
+		defer cancel()
+		registry, err := newNSXTRegistry(ctx, state.client, state.poller, module, filters, h.logger)
+		if err != nil {
+			level.Error(h.logger).Log("msg", "Error creating nsx-t collector", "err", err)
+			http.Error(w, "Error creating nsx-t collector: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		gatherers = append(gatherers, registry)
+	}
+	promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError}).ServeHTTP(w, r)
+}
+
+// probeHandler serves the metrics of the NSX-T manager given by the target
+// parameter, using the module given by the module parameter.
+type probeHandler struct {
+	reloader      *reloader
+	timeoutOffset time.Duration
+	logger        log.Logger
+}
+
+func (h *probeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	target := params.Get("target")
+	if target == "" {
+		http.Error(w, "Target parameter is missing", http.StatusBadRequest)
+		return
+	}
+	moduleName := params.Get("module")
+	if moduleName == "" {
+		moduleName = defaultModule
+	}
+	module, ok := h.reloader.current().modules[moduleName]
+	if !ok {
+		http.Error(w, "Unknown module "+moduleName, http.StatusBadRequest)
+		return
+	}
+	if status, err := checkProbeTarget(target, module); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	logger := log.With(h.logger, "target", target, "module", moduleName)
+	filters, err := collectorFilters(r, module)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Couldn't create filtered metrics handler: %s", err), http.StatusBadRequest)
+		return
+	}
+	ctx, cancel, err := scrapeContext(r, h.timeoutOffset)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Couldn't parse scrape timeout: %s", err), http.StatusBadRequest)
+		return
+	}
+	defer cancel()
+	opts := newNSXTOpts(target, module, h.reloader.throttle)
+	opts.timeout = requestTimeout(ctx, opts.timeout)
+	nsxtClient, session, err := newNSXTClient(opts, logger)
+	if err != nil {
+		level.Error(logger).Log("msg", "Error creating nsx-t client", "err", err)
+		http.Error(w, "Error creating nsx-t client: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if session != nil {
+		defer func() {
+			if err := session.Logout(r.Context()); err != nil {
+				level.Warn(logger).Log("msg", "Error logging out of NSX-T", "err", err)
+			}
+		}()
+	}
+	registry, err := newNSXTRegistry(ctx, nsxtClient, nil, module, filters, logger)
+	if err != nil {
+		level.Error(logger).Log("msg", "Error creating nsx-t collector", "err", err)
+		http.Error(w, "Error creating nsx-t collector: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError}).ServeHTTP(w, r)
+}
+
+// checkProbeTarget returns an error, together with the status to answer with,
+// unless target is a single host listed in the targets of the module. The
+// credentials of the module are only ever sent to those hosts.
+func checkProbeTarget(target string, module config.Module) (int, error) {
+	if strings.Contains(target, ",") {
+		return http.StatusBadRequest, fmt.Errorf("target %q must be a single host", target)
+	}
+	if !contains(module.Targets, target) {
+		return http.StatusForbidden, fmt.Errorf("target %q is not allowed for the module", target)
+	}
+	return 0, nil
+}
+
+// scrapeContext returns a context of the request that expires before the
+// scrape timeout given by Prometheus in the X-Prometheus-Scrape-Timeout-Seconds
+// header, leaving offset to send the response. Without the header, the context
+// only ends when the request does.
+func scrapeContext(r *http.Request, offset time.Duration) (context.Context, context.CancelFunc, error) {
+	v := r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds")
+	if v == "" {
+		ctx, cancel := context.WithCancel(r.Context())
+		return ctx, cancel, nil
+	}
+	seconds, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse timeout from Prometheus header: %s", err)
+	}
+	timeout := time.Duration(seconds * float64(time.Second))
+	if timeout > offset {
+		timeout -= offset
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	return ctx, cancel, nil
+}
+
+// requestTimeout returns timeout, shortened to the time left until the
+// deadline of ctx. The generated client doesn't cancel requests with their
+// context, so the timeout of the HTTP client is what ends abandoned requests.
+func requestTimeout(ctx context.Context, timeout time.Duration) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return timeout
+	}
+	if left := time.Until(deadline); left > 0 && (timeout <= 0 || left < timeout) {
+		return left
+	}
+	return timeout
+}
+
+// newNSXTRegistry returns a registry with the given collectors for the client,
+// adding the labels of the module to every metric. Collectors run by the
+// poller, which may be nil, serve the metrics of their last run.
+func newNSXTRegistry(ctx context.Context, nsxtClient *client.APIClient, poller *collector.Poller, module config.Module, filters []string, logger log.Logger) (*prometheus.Registry, error) {
+	nsxtCollector, err := collector.NewNSXTCollector(ctx, nsxtClient, poller, logger, filters...)
+	if err != nil {
+		return nil, err
+	}
+	registry := prometheus.NewRegistry()
+	if err := prometheus.WrapRegistererWith(module.Labels, registry).Register(nsxtCollector); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// collectorFilters returns the collectors requested by the collect[]
+// parameters of the request, or the collectors of the module without those
+// given by exclude[] parameters. Only collectors that run for the module
+// without parameters can be requested. Without parameters, the collectors of
+// the module are returned.
+func collectorFilters(r *http.Request, module config.Module) ([]string, error) {
+	params := r.URL.Query()
+	filters, excludes := params["collect[]"], params["exclude[]"]
+	if len(filters) == 0 && len(excludes) == 0 {
+		return module.Collectors, nil
+	}
+	if len(filters) > 0 && len(excludes) > 0 {
+		return nil, fmt.Errorf("collect[] and exclude[] cannot be used together")
+	}
+	if err := collector.CheckCollectors(append(filters, excludes...)...); err != nil {
+		return nil, err
+	}
+	available := module.Collectors
+	if len(available) == 0 {
+		available = collector.EnabledCollectors()
+	}
+	if len(excludes) > 0 {
+		for _, name := range available {
+			if !contains(excludes, name) {
+				filters = append(filters, name)
+			}
+		}
+		if len(filters) == 0 {
+			return nil, fmt.Errorf("all collectors are excluded")
+		}
+		return filters, nil
+	}
+	for _, filter := range filters {
+		if !contains(available, filter) {
+			return nil, fmt.Errorf("disabled collector: %s", filter)
+		}
+	}
+	return filters, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"nsxt_exporter/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckProbeTarget(t *testing.T) {
+	module := config.Module{Targets: []string{"nsx-01.example.com", "nsx-02.example.com"}}
+	testcases := []struct {
+		description    string
+		target         string
+		module         config.Module
+		expectedStatus int
+		expectError    bool
+	}{
+		{
+			description: "Should allow target listed in module",
+			target:      "nsx-02.example.com",
+			module:      module,
+		},
+		{
+			description:    "Should reject target not listed in module",
+			target:         "attacker.example.com",
+			module:         module,
+			expectedStatus: http.StatusForbidden,
+			expectError:    true,
+		},
+		{
+			description:    "Should reject target listing several hosts",
+			target:         "nsx-01.example.com,nsx-02.example.com",
+			module:         module,
+			expectedStatus: http.StatusBadRequest,
+			expectError:    true,
+		},
+		{
+			description:    "Should reject every target when module has no targets",
+			target:         "nsx-01.example.com",
+			module:         config.Module{},
+			expectedStatus: http.StatusForbidden,
+			expectError:    true,
+		},
+	}
+	for _, tc := range testcases {
+		status, err := checkProbeTarget(tc.target, tc.module)
+		assert.Equal(t, tc.expectedStatus, status, tc.description)
+		assert.Equal(t, tc.expectError, err != nil, tc.description)
+	}
+}
+
+func TestCollectorFilters(t *testing.T) {
+	module := config.Module{Collectors: []string{"system", "transport_node", "logical_switch"}}
+	testcases := []struct {
+		description     string
+		query           string
+		expectedFilters []string
+		expectError     bool
+	}{
+		{
+			description:     "Should return collectors of module without parameters",
+			query:           "",
+			expectedFilters: []string{"system", "transport_node", "logical_switch"},
+		},
+		{
+			description:     "Should return collectors given by collect[]",
+			query:           "collect[]=system&collect[]=logical_switch",
+			expectedFilters: []string{"system", "logical_switch"},
+		},
+		{
+			description:     "Should return collectors of module except those given by exclude[]",
+			query:           "exclude[]=transport_node",
+			expectedFilters: []string{"system", "logical_switch"},
+		},
+		{
+			description: "Should return error when collect[] names unknown collector",
+			query:       "collect[]=unknown",
+			expectError: true,
+		},
+		{
+			description: "Should return error when exclude[] names unknown collector",
+			query:       "exclude[]=unknown",
+			expectError: true,
+		},
+		{
+			description: "Should return error when collect[] names collector not run for module",
+			query:       "collect[]=firewall",
+			expectError: true,
+		},
+		{
+			description: "Should return error when collect[] and exclude[] are combined",
+			query:       "collect[]=system&exclude[]=transport_node",
+			expectError: true,
+		},
+		{
+			description: "Should return error when every collector is excluded",
+			query:       "exclude[]=system&exclude[]=transport_node&exclude[]=logical_switch",
+			expectError: true,
+		},
+	}
+	for _, tc := range testcases {
+		r := httptest.NewRequest(http.MethodGet, "/metrics?"+tc.query, nil)
+		filters, err := collectorFilters(r, module)
+		assert.Equal(t, tc.expectError, err != nil, tc.description)
+		assert.ElementsMatch(t, tc.expectedFilters, filters, tc.description)
+	}
+}
+
+func TestScrapeContext(t *testing.T) {
+	testcases := []struct {
+		description      string
+		header           string
+		offset           time.Duration
+		expectedDeadline bool
+		expectedTimeout  time.Duration
+		expectError      bool
+	}{
+		{
+			description:      "Should not set deadline without header",
+			header:           "",
+			offset:           500 * time.Millisecond,
+			expectedDeadline: false,
+		},
+		{
+			description:      "Should subtract offset from scrape timeout",
+			header:           "10",
+			offset:           500 * time.Millisecond,
+			expectedDeadline: true,
+			expectedTimeout:  9500 * time.Millisecond,
+		},
+		{
+			description:      "Should parse fractional scrape timeout",
+			header:           "2.5",
+			offset:           500 * time.Millisecond,
+			expectedDeadline: true,
+			expectedTimeout:  2 * time.Second,
+		},
+		{
+			description:      "Should keep scrape timeout when offset exceeds it",
+			header:           "0.2",
+			offset:           500 * time.Millisecond,
+			expectedDeadline: true,
+			expectedTimeout:  200 * time.Millisecond,
+		},
+		{
+			description: "Should return error when header is invalid",
+			header:      "ten",
+			offset:      500 * time.Millisecond,
+			expectError: true,
+		},
+	}
+	for _, tc := range testcases {
+		r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if tc.header != "" {
+			r.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", tc.header)
+		}
+		start := time.Now()
+		ctx, cancel, err := scrapeContext(r, tc.offset)
+		if tc.expectError {
+			assert.Error(t, err, tc.description)
+			continue
+		}
+		assert.NoError(t, err, tc.description)
+		deadline, ok := ctx.Deadline()
+		assert.Equal(t, tc.expectedDeadline, ok, tc.description)
+		if ok {
+			assert.WithinDuration(t, start.Add(tc.expectedTimeout), deadline, 100*time.Millisecond, tc.description)
+		}
+		cancel()
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Equal(t, 10*time.Second, requestTimeout(context.Background(), 10*time.Second), "Should keep timeout without deadline")
+	assert.Equal(t, 2*time.Second, requestTimeout(ctx, 2*time.Second), "Should keep timeout shorter than deadline")
+	assert.InDelta(t, float64(5*time.Second), float64(requestTimeout(ctx, 10*time.Second)), float64(100*time.Millisecond), "Should shorten timeout to deadline")
+	assert.InDelta(t, float64(5*time.Second), float64(requestTimeout(ctx, 0)), float64(100*time.Millisecond), "Should limit missing timeout to deadline")
+}
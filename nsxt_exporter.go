@@ -1,53 +1,266 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"nsxt_exporter/client"
 	"nsxt_exporter/collector"
+	"nsxt_exporter/config"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/common/promlog"
 	"github.com/prometheus/common/promlog/flag"
 	"github.com/prometheus/common/version"
+	"github.com/prometheus/exporter-toolkit/web"
+	webflag "github.com/prometheus/exporter-toolkit/web/kingpinflag"
 	nsxt "github.com/vmware/go-vmware-nsxt"
 	kingpin "gopkg.in/alecthomas/kingpin.v2"
 )
 
+const defaultModule = "default"
+
 type nsxtOpts struct {
-	host     string
-	username string
-	password string
-	insecure bool
+	host         string
+	username     string
+	usernameFile string
+	password     string
+	passwordFile string
+	insecure     bool
+	caFile       string
+	certFile     string
+	keyFile      string
+	thumbprint   string
+	serverName   string
+	timeout      time.Duration
+	throttle     client.ThrottleOpts
+}
+
+func newNSXTOpts(host string, module config.Module, throttle client.ThrottleOpts) nsxtOpts {
+	return nsxtOpts{
+		host:         host,
+		username:     module.Username,
+		usernameFile: module.UsernameFile,
+		password:     module.Password,
+		passwordFile: module.PasswordFile,
+		insecure:     module.Insecure,
+		caFile:       module.CAFile,
+		certFile:     module.CertFile,
+		keyFile:      module.KeyFile,
+		thumbprint:   module.Thumbprint,
+		serverName:   module.ServerName,
+		timeout:      module.Timeout,
+		throttle:     throttle,
+	}
 }
 
-func newNSXTClient(opts nsxtOpts) (*nsxt.APIClient, error) {
+// newNSXTClient returns a client for the NSX-T manager given by opts together
+// with the session it authenticates with, which should be logged out once the
+// client is no longer used. Clients authenticating with a client certificate
+// don't use a session and return a nil session. The host of opts may list the
+// nodes of a manager cluster separated by commas, which are failed over
+// between.
+func newNSXTClient(opts nsxtOpts, logger log.Logger) (*client.APIClient, *client.Session, error) {
+	hosts := strings.Split(opts.host, ",")
+	for i := range hosts {
+		hosts[i] = strings.TrimSpace(hosts[i])
+	}
 	cfg := nsxt.Configuration{
 		BasePath:           "/api/v1",
-		Host:               opts.host,
+		Host:               hosts[0],
 		Scheme:             "https",
 		UserAgent:          "nsxt_exporter/1.0",
-		ClientAuthCertFile: "",
+		ClientAuthCertFile: opts.certFile,
+		ClientAuthKeyFile:  opts.keyFile,
 		RemoteAuth:         false,
 		UserName:           opts.username,
 		Password:           opts.password,
 		Insecure:           opts.insecure,
+		CAFile:             opts.caFile,
+		// The client retries requests that failed without a response at least
+		// once and panics on a zero retry delay, so keep the delays non-zero.
+		RetriesConfiguration: nsxt.ClientRetriesConfiguration{
+			RetryMinDelay: 500,
+			RetryMaxDelay: 1000,
+		},
+	}
+	if err := nsxt.InitHttpClient(&cfg); err != nil {
+		return nil, nil, err
+	}
+	cfg.HTTPClient.Timeout = opts.timeout
+	if err := configureTLS(cfg.HTTPClient.Transport.(*http.Transport).TLSClientConfig, opts); err != nil {
+		return nil, nil, err
+	}
+	transport := client.NewInstrumentedTransport(cfg.HTTPClient.Transport)
+	if len(hosts) > 1 {
+		transport = client.NewFailoverTransport(transport, hosts, logger)
+	}
+	var session *client.Session
+	user := opts.username
+	if opts.usernameFile != "" {
+		user = opts.usernameFile
+	}
+	if opts.certFile != "" {
+		expiry, err := certificateExpiry(opts.certFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		clientCertificateExpiry.WithLabelValues(opts.certFile).Set(float64(expiry.Unix()))
+		user = opts.certFile
+	} else {
+		session = client.NewSession(transport, opts.credentials, logger)
+		transport = session
+	}
+	cfg.HTTPClient.Transport = client.NewThrottledTransport(transport, opts.host+"/"+user, opts.throttle, logger)
+	apiClient, err := client.NewAPIClient(&cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return apiClient, session, nil
+}
+
+// credentials returns the username and password of opts, reading them from
+// their files if given.
+func (o nsxtOpts) credentials() (string, string, error) {
+	username, password := o.username, o.password
+	if o.usernameFile != "" {
+		content, err := ioutil.ReadFile(o.usernameFile)
+		if err != nil {
+			return "", "", err
+		}
+		username = strings.TrimRight(string(content), "\r\n")
+	}
+	if o.passwordFile != "" {
+		content, err := ioutil.ReadFile(o.passwordFile)
+		if err != nil {
+			return "", "", err
+		}
+		password = strings.TrimRight(string(content), "\r\n")
+	}
+	return username, password, nil
+}
+
+// configureTLS applies the server name and thumbprint of opts to tlsConfig. A
+// manager certificate with the given thumbprint is trusted without verifying
+// it against the CA bundle.
+func configureTLS(tlsConfig *tls.Config, opts nsxtOpts) error {
+	tlsConfig.ServerName = opts.serverName
+	if opts.thumbprint == "" {
+		return nil
+	}
+	thumbprint, err := config.ParseThumbprint(opts.thumbprint)
+	if err != nil {
+		return err
 	}
-	return nsxt.NewAPIClient(&cfg)
+	tlsConfig.InsecureSkipVerify = true
+	tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return fmt.Errorf("no certificate presented by %s", opts.host)
+		}
+		sum := sha256.Sum256(rawCerts[0])
+		if !bytes.Equal(sum[:], thumbprint) {
+			return fmt.Errorf("certificate of %s does not match thumbprint %s", opts.host, opts.thumbprint)
+		}
+		return nil
+	}
+	return nil
+}
+
+// certificateExpiry returns when the first certificate in certFile expires.
+func certificateExpiry(certFile string) (time.Time, error) {
+	content, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return time.Time{}, fmt.Errorf("no certificate found in %s", certFile)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error parsing certificate %s: %s", certFile, err)
+	}
+	return cert.NotAfter, nil
+}
+
+// loadModules returns the modules of the configuration file together with the
+// module given by flags, which is used as default module unless the
+// configuration file defines one. Without a configuration file, or if flags
+// give credentials, the module given by flags is validated like the modules
+// of the file. Otherwise it is left out, e.g. to only probe the modules of the
+// file.
+func loadModules(configFile string, flagModule config.Module) (map[string]config.Module, error) {
+	modules := make(map[string]config.Module)
+	if configFile != "" {
+		cfg, err := config.LoadFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+		for name, module := range cfg.Modules {
+			modules[name] = module
+		}
+	}
+	hasCredentials := flagModule.Username != "" || flagModule.UsernameFile != "" || flagModule.CertFile != ""
+	if _, ok := modules[defaultModule]; !ok && (configFile == "" || hasCredentials) {
+		if err := flagModule.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid default module given by flags: %s", err)
+		}
+		if flagModule.Timeout <= 0 {
+			return nil, fmt.Errorf("--nsxt.timeout must be positive")
+		}
+		modules[defaultModule] = flagModule
+	}
+	for name, module := range modules {
+		if err := collector.CheckCollectors(module.Collectors...); err != nil {
+			return nil, fmt.Errorf("module %s: %s", name, err)
+		}
+	}
+	return modules, nil
 }
 
 func main() {
 	var (
 		listenAddress = kingpin.Flag("web.listen-address", "Address to listen on for web interface and telemetry.").Default(":9744").String()
 		metricsPath   = kingpin.Flag("web.telemetry-path", "Path under which to expose metrics.").Default("/metrics").String()
-		opts          = nsxtOpts{}
+		probePath     = kingpin.Flag("web.probe-path", "Path under which to expose metrics of a target given by the target parameter.").Default("/probe").String()
+		configFile    = kingpin.Flag("config.file", "Path to the configuration file with named modules.").String()
+		timeoutOffset = kingpin.Flag("scrape.timeout-offset", "Offset to subtract from the scrape timeout given by Prometheus to stop querying NSX-T in time.").Default("500ms").Duration()
+		host          = kingpin.Flag("nsxt.host", "URI of NSX-T manager, or comma separated URIs of the nodes of a NSX-T manager cluster to fail over between. Defaults to localhost, set to an empty string to only serve targets through the probe endpoint.").Default("localhost").String()
+		flagModule    = config.Module{}
+		throttle      = client.ThrottleOpts{}
 	)
-	kingpin.Flag("nsxt.host", "URI of NSX-T manager.").Default("localhost").StringVar(&opts.host)
-	kingpin.Flag("nsxt.username", "The username to connect to the NSX-T manager as.").StringVar(&opts.username)
-	kingpin.Flag("nsxt.password", "The password for the NSX-T manager user.").StringVar(&opts.password)
-	kingpin.Flag("nsxt.insecure", "Disable TLS host verification.").Default("true").BoolVar(&opts.insecure)
+	kingpin.Flag("nsxt.username", "The username to connect to the NSX-T manager as. Used by the default module unless defined in the configuration file.").Envar("NSXT_USERNAME").StringVar(&flagModule.Username)
+	kingpin.Flag("nsxt.username-file", "File to read the username from on every new session, instead of --nsxt.username. Used by the default module unless defined in the configuration file.").StringVar(&flagModule.UsernameFile)
+	kingpin.Flag("nsxt.password", "The password for the NSX-T manager user. Used by the default module unless defined in the configuration file.").Envar("NSXT_PASSWORD").StringVar(&flagModule.Password)
+	kingpin.Flag("nsxt.password-file", "File to read the password from on every new session, instead of --nsxt.password. Used by the default module unless defined in the configuration file.").StringVar(&flagModule.PasswordFile)
+	kingpin.Flag("nsxt.cert-file", "Client certificate to authenticate to the NSX-T manager as principal identity instead of username and password. Used by the default module unless defined in the configuration file.").StringVar(&flagModule.CertFile)
+	kingpin.Flag("nsxt.key-file", "Private key of the client certificate given by --nsxt.cert-file.").StringVar(&flagModule.KeyFile)
+	kingpin.Flag("nsxt.ca-file", "CA bundle used to verify the NSX-T manager certificate. Used by the default module unless defined in the configuration file.").StringVar(&flagModule.CAFile)
+	kingpin.Flag("nsxt.thumbprint", "SHA-256 thumbprint of the NSX-T manager certificate. A matching certificate is trusted without verifying it against the CA bundle. Used by the default module unless defined in the configuration file.").StringVar(&flagModule.Thumbprint)
+	kingpin.Flag("nsxt.server-name", "Name to verify the NSX-T manager certificate against instead of the host, e.g. when connecting to the cluster VIP by IP. Used by the default module unless defined in the configuration file.").StringVar(&flagModule.ServerName)
+	kingpin.Flag("nsxt.insecure", "Disable TLS host verification. Cannot be combined with --nsxt.ca-file or --nsxt.server-name. Used by the default module unless defined in the configuration file.").Default("false").BoolVar(&flagModule.Insecure)
+	kingpin.Flag("nsxt.timeout", "Timeout of a single NSX-T API request. Should not exceed the scrape timeout. Used by the default module unless defined in the configuration file.").Default(config.DefaultTimeout.String()).DurationVar(&flagModule.Timeout)
+	kingpin.Flag("nsxt.probe-target", "Host that may be scraped through the probe endpoint with the default module. Can be repeated. Used by the default module unless defined in the configuration file.").StringsVar(&flagModule.Targets)
+
+	kingpin.Flag("nsxt.rate-limit", "Maximum number of requests per second to a NSX-T manager per user. No limit if 0.").Default("0").Float64Var(&throttle.RateLimit)
+	kingpin.Flag("nsxt.rate-burst", "Number of requests that may exceed --nsxt.rate-limit at once.").Default("10").IntVar(&throttle.RateBurst)
+	kingpin.Flag("nsxt.max-retries", "Number of times a request rejected by the NSX-T manager with 429 or 503 is retried.").Default("3").IntVar(&throttle.MaxRetries)
+	kingpin.Flag("nsxt.retry-backoff", "Time to wait before retrying a rejected request unless the NSX-T manager sends Retry-After. Doubles on every retry.").Default("1s").DurationVar(&throttle.RetryBackoff)
 
+	webConfig := webflag.AddFlags(kingpin.CommandLine)
 	promlogConfig := &promlog.Config{}
 	flag.AddFlags(kingpin.CommandLine, promlogConfig)
 	kingpin.HelpFlag.Short('h')
@@ -56,29 +269,88 @@ func main() {
 
 	level.Info(logger).Log("msg", "Starting nsxt_exporter", "version", version.Info())
 	level.Info(logger).Log("msg", "Build context", "context", version.BuildContext())
+	level.Info(logger).Log("msg", "Enabled collectors")
+	for _, c := range collector.EnabledCollectors() {
+		level.Info(logger).Log("collector", c)
+	}
 
-	nsxtClient, err := newNSXTClient(opts)
-	if err != nil {
-		level.Error(logger).Log("msg", "Error creating nsx-t client", "err", err)
+	reloader := &reloader{
+		configFile:    *configFile,
+		host:          *host,
+		flagModule:    flagModule,
+		throttle:      throttle,
+		timeoutOffset: *timeoutOffset,
+		logger:        logger,
+	}
+	if err := reloader.reload(); err != nil {
+		level.Error(logger).Log("msg", "Error loading config", "err", err)
 		os.Exit(1)
 	}
-
-	collector := collector.NewNSXTCollector(nsxtClient, logger)
-	prometheus.MustRegister(collector)
 	prometheus.MustRegister(version.NewCollector("nsxt_exporter"))
 
+	term := make(chan os.Signal, 1)
+	signal.Notify(term, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-term
+		level.Info(logger).Log("msg", "Received shutdown signal, logging out of NSX-T")
+		reloader.close()
+		os.Exit(0)
+	}()
+
+	hup := make(chan os.Signal, 1)
+	reloadCh := make(chan chan error)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-hup:
+				if err := reloader.reload(); err != nil {
+					level.Error(logger).Log("msg", "Error reloading config", "err", err)
+					continue
+				}
+				level.Info(logger).Log("msg", "Reloaded config file")
+			case rc := <-reloadCh:
+				if err := reloader.reload(); err != nil {
+					level.Error(logger).Log("msg", "Error reloading config", "err", err)
+					rc <- err
+				} else {
+					level.Info(logger).Log("msg", "Reloaded config file")
+					rc <- nil
+				}
+			}
+		}
+	}()
+
 	level.Info(logger).Log("msg", "Listening on address", "address", *listenAddress)
-	http.Handle(*metricsPath, promhttp.Handler())
+	http.Handle(*metricsPath, promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		&metricsHandler{reloader: reloader, timeoutOffset: *timeoutOffset, logger: logger},
+	))
+	http.Handle(*probePath, &probeHandler{reloader: reloader, timeoutOffset: *timeoutOffset, logger: logger})
+	http.HandleFunc("/-/reload", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			fmt.Fprintf(w, "This endpoint requires a POST request.\n")
+			return
+		}
+		rc := make(chan error)
+		reloadCh <- rc
+		if err := <-rc; err != nil {
+			http.Error(w, fmt.Sprintf("failed to reload config: %s", err), http.StatusInternalServerError)
+		}
+	})
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(`<html>
 		<head><title>NSX-T Exporter</title></head>
 		<body>
 		<h1>NSX-T Exporter</h1>
-		<p><a href="` + *metricsPath + `"></p>
+		<p><a href="` + *metricsPath + `">Metrics</a></p>
+		<p>Probe a target with <code>` + *probePath + `?target=&lt;target&gt;</code>, substituting a target allowed by the targets of the module.</p>
 		</body>
 		</html>`))
 	})
-	if err := http.ListenAndServe(*listenAddress, nil); err != nil {
+	server := &http.Server{Addr: *listenAddress}
+	if err := web.ListenAndServe(server, *webConfig, logger); err != nil {
 		level.Error(logger).Log("msg", "Error starting HTTP server", "err", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"io/ioutil"
+	"nsxt_exporter/config"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadModules(t *testing.T) {
+	testcases := []struct {
+		description           string
+		config                string
+		flagModule            config.Module
+		expectedDefaultModule bool
+		expectError           bool
+	}{
+		{
+			description:           "Should use module given by flags as default module",
+			flagModule:            config.Module{Username: "admin", Password: "secret", Timeout: time.Second},
+			expectedDefaultModule: true,
+		},
+		{
+			description: "Should return error when flags set both username and username file",
+			flagModule:  config.Module{Username: "admin", UsernameFile: "config/testdata/username", Timeout: time.Second},
+			expectError: true,
+		},
+		{
+			description: "Should return error when flags set client certificate without key",
+			flagModule:  config.Module{CertFile: "config/testdata/client.pem", Timeout: time.Second},
+			expectError: true,
+		},
+		{
+			description: "Should return error when flags set no credentials without configuration file",
+			flagModule:  config.Module{Timeout: time.Second},
+			expectError: true,
+		},
+		{
+			description: "Should return error when flags set no timeout",
+			flagModule:  config.Module{Username: "admin", Password: "secret"},
+			expectError: true,
+		},
+		{
+			description:           "Should use default module of configuration file over flags",
+			config:                "modules:\n  default:\n    username: admin\n",
+			flagModule:            config.Module{Username: "admin", UsernameFile: "config/testdata/username"},
+			expectedDefaultModule: true,
+		},
+		{
+			description:           "Should leave out default module when flags set no credentials besides configuration file",
+			config:                "modules:\n  site_a:\n    username: admin\n",
+			flagModule:            config.Module{Timeout: time.Second},
+			expectedDefaultModule: false,
+		},
+	}
+	for _, tc := range testcases {
+		var configFile string
+		if tc.config != "" {
+			f, err := ioutil.TempFile("", "nsxt_exporter")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.Remove(f.Name())
+			f.WriteString(tc.config)
+			f.Close()
+			configFile = f.Name()
+		}
+		modules, err := loadModules(configFile, tc.flagModule)
+		if tc.expectError {
+			assert.Error(t, err, tc.description)
+			continue
+		}
+		if assert.NoError(t, err, tc.description) {
+			_, ok := modules[defaultModule]
+			assert.Equal(t, tc.expectedDefaultModule, ok, tc.description)
+		}
+	}
+}
+
+func TestReloader_ClientCertificateExpiry(t *testing.T) {
+	r := &reloader{
+		flagModule: config.Module{CertFile: "config/testdata/client.pem", KeyFile: "config/testdata/client-key.pem", Timeout: time.Second},
+		logger:     log.NewNopLogger(),
+	}
+	if assert.NoError(t, r.reload()) {
+		assert.Equal(t, 1, testutil.CollectAndCount(clientCertificateExpiry), "Should report expiry of client certificate")
+	}
+	r.flagModule = config.Module{Username: "admin", Password: "secret", Timeout: time.Second}
+	if assert.NoError(t, r.reload()) {
+		assert.Equal(t, 0, testutil.CollectAndCount(clientCertificateExpiry), "Should drop expiry of client certificate no longer used")
+	}
+	r.close()
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"nsxt_exporter/client"
+	"nsxt_exporter/collector"
+	"nsxt_exporter/config"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// logoutTimeout is how long to wait for a session to be destroyed.
+	logoutTimeout = 10 * time.Second
+	// defaultScrapeTimeout is the scrape timeout of Prometheus unless
+	// configured otherwise.
+	defaultScrapeTimeout = 10 * time.Second
+)
+
+var (
+	configReloadSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "nsxt_exporter",
+		Name:      "config_last_reload_successful",
+		Help:      "Whether the last configuration reload attempt was successful.",
+	})
+	configReloadSeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "nsxt_exporter",
+		Name:      "config_last_reload_success_timestamp_seconds",
+		Help:      "Timestamp of the last successful configuration reload.",
+	})
+	clientCertificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "nsxt_exporter",
+		Name:      "client_certificate_expiry_timestamp_seconds",
+		Help:      "Timestamp when the client certificate used to authenticate to NSX-T expires.",
+	}, []string{"cert_file"})
+)
+
+func init() {
+	prometheus.MustRegister(configReloadSuccess)
+	prometheus.MustRegister(configReloadSeconds)
+	prometheus.MustRegister(clientCertificateExpiry)
+}
+
+// exporterState holds everything built from the configuration. It is replaced
+// as a whole on reload so that requests never see a partially applied
+// configuration.
+type exporterState struct {
+	modules map[string]config.Module
+	// client connects to the manager given by --nsxt.host. It is nil if no
+	// host is given.
+	client *client.APIClient
+	// session authenticates client. It is nil if no host is given.
+	session *client.Session
+	// poller runs the collectors of the default module that have a poll
+	// interval against client. It is nil if no host is given.
+	poller *collector.Poller
+	// scrapes counts the scrapes using client.
+	scrapes sync.WaitGroup
+}
+
+// release marks a scrape returned by reloader.acquire as done.
+func (s *exporterState) release() {
+	s.scrapes.Done()
+}
+
+// close stops the poller and, once running scrapes are done, logs out of the
+// session of the state.
+func (s *exporterState) close(logger log.Logger) {
+	if s.poller != nil {
+		s.poller.Stop()
+	}
+	s.scrapes.Wait()
+	if s.session != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), logoutTimeout)
+		defer cancel()
+		if err := s.session.Logout(ctx); err != nil {
+			level.Warn(logger).Log("msg", "Error logging out of NSX-T", "err", err)
+		}
+	}
+}
+
+type reloader struct {
+	configFile    string
+	host          string
+	flagModule    config.Module
+	throttle      client.ThrottleOpts
+	timeoutOffset time.Duration
+	logger        log.Logger
+
+	mtx   sync.RWMutex
+	state *exporterState
+}
+
+// reload re-reads the configuration and rebuilds the NSX-T clients. The
+// current state is kept if any step fails.
+func (r *reloader) reload() (err error) {
+	defer func() {
+		if err != nil {
+			configReloadSuccess.Set(0)
+		} else {
+			configReloadSuccess.Set(1)
+			configReloadSeconds.SetToCurrentTime()
+		}
+	}()
+	modules, err := loadModules(r.configFile, r.flagModule)
+	if err != nil {
+		return err
+	}
+	state := &exporterState{
+		modules: modules,
+	}
+	if r.host != "" {
+		if _, ok := modules[defaultModule]; !ok {
+			return fmt.Errorf("no default module to scrape %s with", r.host)
+		}
+		state.client, state.session, err = newNSXTClient(newNSXTOpts(r.host, modules[defaultModule], r.throttle), r.logger)
+		if err != nil {
+			return fmt.Errorf("error creating nsx-t client: %s", err)
+		}
+		var previous *collector.Poller
+		if old := r.current(); old != nil {
+			previous = old.poller
+		}
+		state.poller, err = collector.NewPoller(state.client, previous, r.logger, modules[defaultModule].Collectors...)
+		if err != nil {
+			state.close(r.logger)
+			return fmt.Errorf("error creating poller: %s", err)
+		}
+	}
+	r.mtx.Lock()
+	old := r.state
+	r.state = state
+	r.mtx.Unlock()
+	if old != nil {
+		old.close(r.logger)
+	}
+	updateCertificateExpiry(modules, r.logger)
+	r.checkTimeouts(modules)
+	return nil
+}
+
+// updateCertificateExpiry replaces the expiry of client certificates with
+// those of the given modules, so that certificates no longer used are dropped.
+func updateCertificateExpiry(modules map[string]config.Module, logger log.Logger) {
+	clientCertificateExpiry.Reset()
+	for name, module := range modules {
+		if module.CertFile == "" {
+			continue
+		}
+		expiry, err := certificateExpiry(module.CertFile)
+		if err != nil {
+			level.Warn(logger).Log("msg", "Unable to read client certificate expiry", "module", name, "err", err)
+			continue
+		}
+		clientCertificateExpiry.WithLabelValues(module.CertFile).Set(float64(expiry.Unix()))
+	}
+}
+
+// checkTimeouts warns about modules whose requests may outlive a scrape with
+// the default scrape timeout.
+func (r *reloader) checkTimeouts(modules map[string]config.Module) {
+	for name, module := range modules {
+		if module.Timeout > defaultScrapeTimeout-r.timeoutOffset {
+			level.Warn(r.logger).Log("msg", "NSX-T API request timeout exceeds the default scrape timeout less the scrape timeout offset, requests may outlive scrapes", "module", name, "timeout", module.Timeout, "scrape_timeout", defaultScrapeTimeout)
+		}
+	}
+}
+
+// close stops the current state, e.g. on shutdown.
+func (r *reloader) close() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.state != nil {
+		r.state.close(r.logger)
+	}
+}
+
+// acquire returns the current state for a scrape, which must release it once
+// done. The session of the state isn't logged out before.
+func (r *reloader) acquire() *exporterState {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	r.state.scrapes.Add(1)
+	return r.state
+}
+
+func (r *reloader) current() *exporterState {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.state
+}